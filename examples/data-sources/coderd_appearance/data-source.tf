@@ -0,0 +1,9 @@
+data "coderd_appearance" "current" {}
+
+// Fail the plan if the deployment's branding has drifted
+check "branding" {
+  assert {
+    condition     = data.coderd_appearance.current.application_name == "Example Corp Coder"
+    error_message = "The application name of the deployment has drifted."
+  }
+}
@@ -0,0 +1,16 @@
+// Daily active users across the whole deployment
+data "coderd_daus" "deployment" {}
+
+// Daily active users of a single template, bucketed by US Eastern days
+data "coderd_template" "ubuntu-main" {
+  name = "ubuntu-main"
+}
+
+data "coderd_daus" "ubuntu-main" {
+  template_id    = data.coderd_template.ubuntu-main.id
+  tz_hour_offset = -5
+}
+
+output "deployment_daus" {
+  value = { for entry in data.coderd_daus.deployment.entries : entry.date => entry.amount }
+}
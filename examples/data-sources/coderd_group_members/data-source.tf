@@ -0,0 +1,23 @@
+data "coderd_group" "engineering" {
+  name = "engineering"
+}
+
+// Retrieve the members of a large group in pages of 1000
+data "coderd_group_members" "first_page" {
+  group_id = data.coderd_group.engineering.id
+  limit    = 1000
+}
+
+data "coderd_group_members" "remaining" {
+  count    = ceil(data.coderd_group_members.first_page.total_count / 1000) - 1
+  group_id = data.coderd_group.engineering.id
+  offset   = (count.index + 1) * 1000
+  limit    = 1000
+}
+
+locals {
+  engineering_emails = concat(
+    data.coderd_group_members.first_page.members[*].email,
+    flatten([for page in data.coderd_group_members.remaining : page.members[*].email]),
+  )
+}
@@ -0,0 +1,14 @@
+data "coderd_import_inventory" "all" {}
+
+# Write out import blocks for every template, which can be saved to a file
+# and used to generate their configuration with
+# `terraform plan -generate-config-out=generated.tf`.
+output "template_import_blocks" {
+  value = join("\n", [for template in data.coderd_import_inventory.all.templates : <<-EOT
+    import {
+      to = coderd_template.${replace(template.name, "-", "_")}
+      id = "${template.import_id}"
+    }
+    EOT
+  ])
+}
@@ -0,0 +1,14 @@
+data "coderd_regions" "all" {}
+
+// Create a DNS record for the wildcard hostname of each region
+resource "aws_route53_record" "apps" {
+  for_each = {
+    for region in data.coderd_regions.all.regions : region.name => region
+    if region.wildcard_hostname != ""
+  }
+  zone_id = var.zone_id
+  name    = each.value.wildcard_hostname
+  type    = "CNAME"
+  ttl     = 300
+  records = [trimprefix(each.value.path_app_url, "https://")]
+}
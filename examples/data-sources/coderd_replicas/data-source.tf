@@ -0,0 +1,8 @@
+data "coderd_replicas" "all" {}
+
+check "replicas_healthy" {
+  assert {
+    condition     = alltrue([for replica in data.coderd_replicas.all.replicas : replica.error == ""])
+    error_message = "One or more coderd replicas are unable to reach their peers."
+  }
+}
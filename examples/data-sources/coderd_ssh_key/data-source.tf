@@ -0,0 +1,9 @@
+data "coderd_ssh_key" "admin" {
+  username = "admin"
+}
+
+// Allow the user's workspaces to clone from GitHub
+resource "github_user_ssh_key" "coder" {
+  title = "Coder workspaces"
+  key   = data.coderd_ssh_key.admin.public_key
+}
@@ -0,0 +1,6 @@
+// List the starter templates shipped with the deployment
+data "coderd_starter_templates" "all" {}
+
+output "starter_template_ids" {
+  value = [for t in data.coderd_starter_templates.all.starter_templates : t.id]
+}
@@ -0,0 +1,17 @@
+variable "team_templates" {
+  type        = map(string)
+  description = "The IDs of the templates owned by each team."
+}
+
+data "coderd_template_daus" "team" {
+  for_each    = var.team_templates
+  template_id = each.value
+  interval    = "week"
+}
+
+# The number of users of each team's template last week.
+output "weekly_users" {
+  value = {
+    for team, daus in data.coderd_template_daus.team : team => daus.entries[length(daus.entries) - 1].active_users
+  }
+}
@@ -0,0 +1,13 @@
+data "coderd_template" "ubuntu-main" {
+  name = "ubuntu-main"
+}
+
+// Fork the active version of the template into a local directory
+data "coderd_template_version_files" "ubuntu-main" {
+  template_version_id = data.coderd_template.ubuntu-main.active_version_id
+  output_directory    = "${path.module}/ubuntu-fork"
+}
+
+output "main_tf" {
+  value = data.coderd_template_version_files.ubuntu-main.files["main.tf"]
+}
@@ -0,0 +1,12 @@
+data "coderd_template" "ubuntu-main" {
+  name = "ubuntu-main"
+}
+
+data "coderd_template_version_presets" "ubuntu-main" {
+  template_version_id = data.coderd_template.ubuntu-main.active_version_id
+}
+
+// Look up a preset's parameters by name
+locals {
+  large_preset = one([for p in data.coderd_template_version_presets.ubuntu-main.presets : p if p.name == "Large"])
+}
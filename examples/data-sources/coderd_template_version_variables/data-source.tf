@@ -0,0 +1,12 @@
+data "coderd_template" "ubuntu-main" {
+  name = "ubuntu-main"
+}
+
+data "coderd_template_version_variables" "ubuntu-main" {
+  template_version_id = data.coderd_template.ubuntu-main.active_version_id
+}
+
+// Names of the variables that must be supplied when pushing a new version
+output "required_variables" {
+  value = [for v in data.coderd_template_version_variables.ubuntu-main.variables : v.name if v.required]
+}
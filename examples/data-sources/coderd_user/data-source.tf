@@ -8,12 +8,18 @@ data "coderd_user" "admin" {
   username = "admin"
 }
 
+// Get a user on the Coder deployment by `email`
+data "coderd_user" "developer" {
+  email = "developer@example.com"
+}
+
 
 // Use them to create a group
 resource "coderd_group" "bosses" {
   name = "group"
   members = [
     data.coderd_user.admin.id,
-    data.coderd_user.manager.id
+    data.coderd_user.manager.id,
+    data.coderd_user.developer.id
   ]
 }
@@ -0,0 +1,14 @@
+variable "license_seats" {
+  type = number
+}
+
+data "coderd_user_status_counts" "seats" {}
+
+locals {
+  # Dormant users don't consume a seat.
+  active_users = data.coderd_user_status_counts.seats.active[length(data.coderd_user_status_counts.seats.active) - 1].count
+}
+
+output "seat_usage_percent" {
+  value = floor(100 * local.active_users / var.license_seats)
+}
@@ -0,0 +1,13 @@
+data "coderd_workspace_agents" "dev" {
+  owner = "alice"
+  name  = "dev"
+}
+
+# The URLs of the apps of the workspace's connected agents.
+output "app_urls" {
+  value = flatten([
+    for agent in data.coderd_workspace_agents.dev.agents : [
+      for app in agent.apps : app.url
+    ] if agent.status == "connected"
+  ])
+}
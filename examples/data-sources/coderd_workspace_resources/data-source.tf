@@ -0,0 +1,14 @@
+variable "workspaces" {
+  type        = set(string)
+  description = "The workspaces to report the costs of, as `<owner>/<name>`."
+}
+
+data "coderd_workspace_resources" "fleet" {
+  for_each = var.workspaces
+  owner    = split("/", each.value)[0]
+  name     = split("/", each.value)[1]
+}
+
+output "daily_costs" {
+  value = { for workspace, resources in data.coderd_workspace_resources.fleet : workspace => resources.daily_cost }
+}
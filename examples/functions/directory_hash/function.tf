@@ -0,0 +1,4 @@
+# The hash the provider computes for a template version with this directory and exclude.
+output "template_hash" {
+  value = provider::coderd::directory_hash("${path.module}/template", ["*.md"])
+}
@@ -0,0 +1,8 @@
+variable "template" {
+  type        = string
+  description = "The ID of the template, or its `<organization-name>/<template-name>`."
+}
+
+locals {
+  template_is_id = provider::coderd::is_uuid(var.template)
+}
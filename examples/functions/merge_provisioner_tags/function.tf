@@ -0,0 +1,12 @@
+variable "extra_provisioner_tags" {
+  type    = map(string)
+  default = null
+}
+
+locals {
+  # Tags set by a module's caller take precedence over the module's own.
+  provisioner_tags = provider::coderd::merge_provisioner_tags(
+    { region = "us-east", arch = "amd64" },
+    var.extra_provisioner_tags,
+  )
+}
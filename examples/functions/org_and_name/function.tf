@@ -0,0 +1,7 @@
+locals {
+  template = provider::coderd::org_and_name("default/kubernetes")
+}
+
+data "coderd_organization" "template" {
+  name = local.template.organization_name
+}
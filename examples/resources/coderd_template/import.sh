@@ -0,0 +1,5 @@
+# Import by template ID
+terraform import coderd_template.example 8bd2ad3d-4f8d-4f79-9b7d-1d8e8a5b0c7e
+
+# Import by organization name and template name
+terraform import coderd_template.example default/example-template
@@ -17,14 +17,32 @@ resource "coderd_template" "ubuntu-main" {
   description = "The main template for developing on Ubuntu."
   versions = [
     {
-      name        = "stable-${var.TFC_CONFIGURATION_VERSION_GIT_COMMIT_SHA}"
-      description = "The stable version of the template."
-      directory   = "./stable-template"
+      name      = "stable-${var.TFC_CONFIGURATION_VERSION_GIT_COMMIT_SHA}"
+      message   = "The stable version of the template."
+      directory = "./stable-template"
     },
     {
-      name        = "staging-${var.TFC_CONFIGURATION_VERSION_GIT_COMMIT_SHA}"
-      description = "The staging version of the template."
-      directory   = "./staging-template"
+      name      = "staging-${var.TFC_CONFIGURATION_VERSION_GIT_COMMIT_SHA}"
+      message   = "The staging version of the template."
+      directory = "./staging-template"
+    },
+    {
+      // Named after the date and the contents of the repository, e.g. `2024-06-01-abc1234`
+      name_template = "{{.Date}}-{{.Hash}}"
+      message       = "The nightly version of the template, from the templates repository."
+      git = {
+        url          = "https://github.com/example/coder-templates.git"
+        ref          = "main"
+        subdirectory = "ubuntu"
+      }
+    },
+    {
+      name    = "release-1.2.0"
+      message = "A released version of the template, built by CI."
+      archive = {
+        url    = "https://example.com/releases/ubuntu-1.2.0.tar.gz"
+        sha256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+      }
     }
   ]
   acl = {
@@ -34,4 +52,13 @@ resource "coderd_template" "ubuntu-main" {
     }]
     groups = []
   }
+  // Restart workspaces every other weekend, and only allow autostart on weekdays
+  auto_stop_requirement = {
+    days_of_week = ["saturday", "sunday"]
+    weeks        = 2
+  }
+  auto_start_permitted_days_of_week = ["monday", "tuesday", "wednesday", "thursday", "friday"]
+  // Mark workspaces dormant after a week of inactivity, and delete them a month later
+  time_til_dormant            = "168h"
+  time_til_dormant_autodelete = "720h"
 }
@@ -0,0 +1,167 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AppearanceDataSource{}
+
+func NewAppearanceDataSource() datasource.DataSource {
+	return &AppearanceDataSource{}
+}
+
+// AppearanceDataSource defines the data source implementation.
+type AppearanceDataSource struct {
+	data *CoderdProviderData
+}
+
+// AppearanceDataSourceModel describes the data source data model.
+type AppearanceDataSourceModel struct {
+	ApplicationName     types.String  `tfsdk:"application_name"`
+	LogoURL             types.String  `tfsdk:"logo_url"`
+	AnnouncementBanners []Banner      `tfsdk:"announcement_banners"`
+	SupportLinks        []SupportLink `tfsdk:"support_links"`
+}
+
+type Banner struct {
+	Enabled         types.Bool   `tfsdk:"enabled"`
+	Message         types.String `tfsdk:"message"`
+	BackgroundColor types.String `tfsdk:"background_color"`
+}
+
+type SupportLink struct {
+	Name   types.String `tfsdk:"name"`
+	Target types.String `tfsdk:"target"`
+	Icon   types.String `tfsdk:"icon"`
+}
+
+func (d *AppearanceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appearance"
+}
+
+func (d *AppearanceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The current appearance settings of the Coder deployment.\n\n" +
+			"Customizing the appearance of a deployment requires an Enterprise license. On other deployments, the defaults are returned.",
+
+		Attributes: map[string]schema.Attribute{
+			"application_name": schema.StringAttribute{
+				MarkdownDescription: "The application name displayed in the dashboard.",
+				Computed:            true,
+			},
+			"logo_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the logo displayed in the dashboard.",
+				Computed:            true,
+			},
+			"announcement_banners": schema.ListNestedAttribute{
+				MarkdownDescription: "Announcement banners displayed at the top of the dashboard.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"enabled": schema.BoolAttribute{
+							MarkdownDescription: "Whether the banner is displayed.",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "The message displayed in the banner.",
+							Computed:            true,
+						},
+						"background_color": schema.StringAttribute{
+							MarkdownDescription: "The background color of the banner, as a hex color code.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"support_links": schema.ListNestedAttribute{
+				MarkdownDescription: "Links displayed in the dashboard's support menu.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the link.",
+							Computed:            true,
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "The URL the link points to.",
+							Computed:            true,
+						},
+						"icon": schema.StringAttribute{
+							MarkdownDescription: "The icon displayed next to the link.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AppearanceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *AppearanceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AppearanceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	appearance, err := client.Appearance(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get appearance settings, got error: %s", err))
+		return
+	}
+
+	data.ApplicationName = types.StringValue(appearance.ApplicationName)
+	data.LogoURL = types.StringValue(appearance.LogoURL)
+	banners := make([]Banner, 0, len(appearance.AnnouncementBanners))
+	for _, banner := range appearance.AnnouncementBanners {
+		banners = append(banners, Banner{
+			Enabled:         types.BoolValue(banner.Enabled),
+			Message:         types.StringValue(banner.Message),
+			BackgroundColor: types.StringValue(banner.BackgroundColor),
+		})
+	}
+	data.AnnouncementBanners = banners
+	links := make([]SupportLink, 0, len(appearance.SupportLinks))
+	for _, link := range appearance.SupportLinks {
+		links = append(links, SupportLink{
+			Name:   types.StringValue(link.Name),
+			Target: types.StringValue(link.Target),
+			Icon:   types.StringValue(link.Icon),
+		})
+	}
+	data.SupportLinks = links
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccAppearanceDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "appearance_data_acc", false)
+
+	cfg := testAccAppearanceDataSourceConfig{
+		URL:   client.URL.String(),
+		Token: client.SessionToken(),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.coderd_appearance.test", "application_name", ""),
+					resource.TestCheckResourceAttr("data.coderd_appearance.test", "logo_url", ""),
+					resource.TestCheckResourceAttr("data.coderd_appearance.test", "announcement_banners.#", "0"),
+					resource.TestCheckResourceAttrSet("data.coderd_appearance.test", "support_links.#"),
+				),
+			},
+		},
+	})
+}
+
+type testAccAppearanceDataSourceConfig struct {
+	URL   string
+	Token string
+}
+
+func (c testAccAppearanceDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_appearance" "test" {}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("appearanceDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
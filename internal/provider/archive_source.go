@@ -0,0 +1,291 @@
+package provider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/coder/coder/v2/provisionersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ArchiveSource is a pre-built archive to create a template version from.
+type ArchiveSource struct {
+	Path   types.String `tfsdk:"path"`
+	URL    types.String `tfsdk:"url"`
+	SHA256 types.String `tfsdk:"sha256"`
+}
+
+var sha256Regex = regexp.MustCompile("^[a-f0-9]{64}$")
+
+var archiveSourceAttribute = schema.SingleNestedAttribute{
+	MarkdownDescription: "A pre-built `.tar`, `.tar.gz`, `.tgz` or `.zip` archive to create the template version from, instead of a local `directory`. " +
+		"The archive is extracted on the machine running Terraform, and its contents are uploaded to Coder. " +
+		"Changes in the contents of the archive will trigger the creation of a new template version.",
+	Optional: true,
+	Attributes: map[string]schema.Attribute{
+		"path": schema.StringAttribute{
+			MarkdownDescription: "A path to a local archive. Exactly one of `path` or `url` must be set.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("url")),
+			},
+		},
+		"url": schema.StringAttribute{
+			MarkdownDescription: "An HTTP(S) URL to download the archive from.",
+			Optional:            true,
+		},
+		"sha256": schema.StringAttribute{
+			MarkdownDescription: "The expected SHA-256 checksum of the archive, in lowercase hex. If set, the archive is rejected if it doesn't match.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(sha256Regex, "must be a lowercase hex SHA-256 checksum"),
+			},
+		},
+	},
+}
+
+// extractArchiveSource extracts the archive into a temporary directory, which
+// is removed by calling cleanup.
+func extractArchiveSource(ctx context.Context, src *ArchiveSource) (dir string, cleanup func(), err error) {
+	tmpDir, err := os.MkdirTemp("", "coderd-archive-source-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	name := src.Path.ValueString()
+	archivePath := name
+	if !src.URL.IsNull() {
+		u, err := url.Parse(src.URL.ValueString())
+		if err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("invalid archive url: %w", err)
+		}
+		name = u.Path
+		archivePath = filepath.Join(tmpDir, "archive")
+		tflog.Info(ctx, "downloading template archive", map[string]any{
+			"url": u.Redacted(),
+		})
+		err = downloadFile(ctx, u.String(), archivePath)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	if !src.SHA256.IsNull() {
+		err = verifySHA256(archivePath, src.SHA256.ValueString())
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	dir = filepath.Join(tmpDir, "contents")
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		err = extractZip(archivePath, dir)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		err = extractTar(archivePath, dir, true)
+	case strings.HasSuffix(lower, ".tar"):
+		err = extractTar(archivePath, dir, false)
+	default:
+		err = fmt.Errorf("unsupported archive %q: must be a .tar, .tar.gz, .tgz or .zip file", name)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	tflog.Info(ctx, "successfully extracted template archive")
+	return dir, cleanup, nil
+}
+
+func downloadFile(ctx context.Context, rawURL, dst string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download archive: unexpected status %s", res.Status)
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, io.LimitReader(res.Body, provisionersdk.TemplateArchiveLimit+1))
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	return f.Close()
+}
+
+func verifySHA256(file, expected string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return fmt.Errorf("archive checksum mismatch: expected sha256 %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// archiveWriter writes archive entries into a directory, rejecting entries
+// outside of it and limiting the total extracted size.
+type archiveWriter struct {
+	dir     string
+	written int64
+}
+
+func (w *archiveWriter) target(name string) (string, error) {
+	name = filepath.FromSlash(strings.TrimPrefix(name, "./"))
+	if name == "" {
+		return w.dir, nil
+	}
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("archive entry %q is outside of the archive", name)
+	}
+	return filepath.Join(w.dir, name), nil
+}
+
+func (w *archiveWriter) mkdir(name string) error {
+	target, err := w.target(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(target, 0o755)
+}
+
+func (w *archiveWriter) writeFile(name string, mode os.FileMode, r io.Reader) error {
+	target, err := w.target(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	remaining := provisionersdk.TemplateArchiveLimit - w.written
+	n, err := io.Copy(f, io.LimitReader(r, remaining+1))
+	w.written += n
+	if err != nil {
+		return err
+	}
+	if w.written > provisionersdk.TemplateArchiveLimit {
+		return fmt.Errorf("archive too big: contents exceed the limit of %d bytes", provisionersdk.TemplateArchiveLimit)
+	}
+	return f.Close()
+}
+
+func extractTar(file, dir string, gzipped bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("failed to decompress archive: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	w := &archiveWriter{dir: dir}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = w.mkdir(header.Name)
+		case tar.TypeReg:
+			err = w.writeFile(header.Name, header.FileInfo().Mode(), tr)
+		default:
+			// Links and special files aren't supported in templates.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(file, dir string) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer zr.Close()
+	w := &archiveWriter{dir: dir}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, entry := range zr.File {
+		mode := entry.Mode()
+		switch {
+		case mode.IsDir():
+			err = w.mkdir(entry.Name)
+		case mode.IsRegular():
+			var rc io.ReadCloser
+			rc, err = entry.Open()
+			if err != nil {
+				return fmt.Errorf("failed to read archive: %w", err)
+			}
+			err = w.writeFile(entry.Name, mode, rc)
+			rc.Close()
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
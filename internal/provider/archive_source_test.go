@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractArchiveSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	files := map[string]string{
+		"main.tf":          "# main",
+		"modules/agent.tf": "# agent",
+	}
+
+	var tarBuf bytes.Buffer
+	gz := gzip.NewWriter(&tarBuf)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0o755}))
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	dir := t.TempDir()
+	tarPath := filepath.Join(dir, "template.tar.gz")
+	require.NoError(t, os.WriteFile(tarPath, tarBuf.Bytes(), 0o600))
+	zipPath := filepath.Join(dir, "template.zip")
+	require.NoError(t, os.WriteFile(zipPath, zipBuf.Bytes(), 0o600))
+	tarSum := sha256.Sum256(tarBuf.Bytes())
+
+	requireContents := func(t *testing.T, dir string) {
+		t.Helper()
+		for name, content := range files {
+			actual, err := os.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err)
+			require.Equal(t, content, string(actual))
+		}
+	}
+
+	t.Run("TarGz", func(t *testing.T) {
+		t.Parallel()
+		dir, cleanup, err := extractArchiveSource(ctx, &ArchiveSource{
+			Path:   types.StringValue(tarPath),
+			SHA256: types.StringValue(hex.EncodeToString(tarSum[:])),
+		})
+		require.NoError(t, err)
+		defer cleanup()
+		requireContents(t, dir)
+	})
+
+	t.Run("Zip", func(t *testing.T) {
+		t.Parallel()
+		dir, cleanup, err := extractArchiveSource(ctx, &ArchiveSource{
+			Path: types.StringValue(zipPath),
+		})
+		require.NoError(t, err)
+		defer cleanup()
+		requireContents(t, dir)
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/releases/template.tar.gz" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(tarBuf.Bytes())
+		}))
+		defer srv.Close()
+
+		dir, cleanup, err := extractArchiveSource(ctx, &ArchiveSource{
+			URL:    types.StringValue(srv.URL + "/releases/template.tar.gz"),
+			SHA256: types.StringValue(hex.EncodeToString(tarSum[:])),
+		})
+		require.NoError(t, err)
+		defer cleanup()
+		requireContents(t, dir)
+
+		_, _, err = extractArchiveSource(ctx, &ArchiveSource{
+			URL: types.StringValue(srv.URL + "/missing.tar.gz"),
+		})
+		require.ErrorContains(t, err, "404")
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := extractArchiveSource(ctx, &ArchiveSource{
+			Path:   types.StringValue(zipPath),
+			SHA256: types.StringValue(hex.EncodeToString(tarSum[:])),
+		})
+		require.ErrorContains(t, err, "checksum mismatch")
+	})
+
+	t.Run("UnsupportedFormat", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := extractArchiveSource(ctx, &ArchiveSource{
+			Path: types.StringValue(filepath.Join(dir, "template.rar")),
+		})
+		require.ErrorContains(t, err, "unsupported archive")
+	})
+
+	t.Run("OutsideArchive", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.tf", Typeflag: tar.TypeReg, Mode: 0o644}))
+		require.NoError(t, tw.Close())
+		evilPath := filepath.Join(t.TempDir(), "evil.tar")
+		require.NoError(t, os.WriteFile(evilPath, buf.Bytes(), 0o600))
+
+		_, _, err := extractArchiveSource(ctx, &ArchiveSource{
+			Path: types.StringValue(evilPath),
+		})
+		require.ErrorContains(t, err, "outside of the archive")
+	})
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+)
+
+// lookupCache caches lookups by name for the lifetime of the provider
+// configuration, so resources and data sources that refer to the same
+// organization or group don't repeat the request. Failed lookups aren't
+// cached.
+type lookupCache struct {
+	mu            sync.Mutex
+	organizations map[string]codersdk.Organization
+	groups        map[groupKey]codersdk.Group
+}
+
+type groupKey struct {
+	organizationID uuid.UUID
+	name           string
+}
+
+// organizationByName returns the organization with the given name.
+func (d *CoderdProviderData) organizationByName(ctx context.Context, name string) (codersdk.Organization, error) {
+	d.cache.mu.Lock()
+	org, ok := d.cache.organizations[name]
+	d.cache.mu.Unlock()
+	if ok {
+		return org, nil
+	}
+	org, err := d.Client.OrganizationByName(ctx, name)
+	if err != nil {
+		return codersdk.Organization{}, err
+	}
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+	if d.cache.organizations == nil {
+		d.cache.organizations = map[string]codersdk.Organization{}
+	}
+	d.cache.organizations[name] = org
+	return org, nil
+}
+
+// groupByName returns the group in the organization with the given name.
+func (d *CoderdProviderData) groupByName(ctx context.Context, orgID uuid.UUID, name string) (codersdk.Group, error) {
+	key := groupKey{organizationID: orgID, name: name}
+	d.cache.mu.Lock()
+	group, ok := d.cache.groups[key]
+	d.cache.mu.Unlock()
+	if ok {
+		return group, nil
+	}
+	group, err := d.Client.GroupByOrgAndName(ctx, orgID, name)
+	if err != nil {
+		return codersdk.Group{}, err
+	}
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+	if d.cache.groups == nil {
+		d.cache.groups = map[groupKey]codersdk.Group{}
+	}
+	d.cache.groups[key] = group
+	return group, nil
+}
+
+// forgetGroups clears the cached groups, as a group was modified.
+func (d *CoderdProviderData) forgetGroups() {
+	d.cache.mu.Lock()
+	defer d.cache.mu.Unlock()
+	d.cache.groups = nil
+}
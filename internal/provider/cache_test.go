@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupCache(t *testing.T) {
+	t.Parallel()
+	orgID := uuid.New()
+	var orgRequests, groupRequests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/organizations/example":
+			orgRequests.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":   orgID,
+				"name": "example",
+			})
+		case "/api/v2/organizations/" + orgID.String() + "/groups/developers":
+			groupRequests.Add(1)
+			_ = json.NewEncoder(w).Encode(codersdk.Group{Name: "developers", OrganizationID: orgID})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(codersdk.Response{Message: "not found"})
+		}
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	data := &CoderdProviderData{Client: codersdk.New(srvURL)}
+	ctx := context.Background()
+
+	for range 3 {
+		org, err := data.organizationByName(ctx, "example")
+		require.NoError(t, err)
+		require.Equal(t, orgID, org.ID)
+		group, err := data.groupByName(ctx, orgID, "developers")
+		require.NoError(t, err)
+		require.Equal(t, "developers", group.Name)
+	}
+	require.EqualValues(t, 1, orgRequests.Load())
+	require.EqualValues(t, 1, groupRequests.Load())
+
+	data.forgetGroups()
+	_, err = data.groupByName(ctx, orgID, "developers")
+	require.NoError(t, err)
+	require.EqualValues(t, 2, groupRequests.Load())
+
+	// Failures aren't cached.
+	_, err = data.organizationByName(ctx, "missing")
+	require.Error(t, err)
+	_, err = data.organizationByName(ctx, "missing")
+	require.Error(t, err)
+}
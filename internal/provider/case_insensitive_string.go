@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type caseInsensitiveStringType struct {
+	basetypes.StringType
+}
+
+var _ basetypes.StringTypable = CaseInsensitiveStringType
+
+// CaseInsensitiveStringType is a string that Coder compares without regard to
+// case, such as a username or email. Values that only differ in case are
+// semantically equal, so the case Coder returns doesn't cause a diff from the
+// configured value.
+var CaseInsensitiveStringType = caseInsensitiveStringType{}
+
+// String implements basetypes.StringTypable.
+func (t caseInsensitiveStringType) String() string {
+	return "CaseInsensitiveString"
+}
+
+func (t caseInsensitiveStringType) ValueType(ctx context.Context) attr.Value {
+	return CaseInsensitiveString{}
+}
+
+// Equal implements basetypes.StringTypable.
+func (t caseInsensitiveStringType) Equal(o attr.Type) bool {
+	if o, ok := o.(caseInsensitiveStringType); ok {
+		return t.StringType.Equal(o.StringType)
+	}
+	return false
+}
+
+// ValueFromString implements basetypes.StringTypable.
+func (t caseInsensitiveStringType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return CaseInsensitiveString{StringValue: in}, nil
+}
+
+// ValueFromTerraform implements basetypes.StringTypable.
+func (t caseInsensitiveStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T, expected basetypes.StringValue", attrValue)
+	}
+
+	return CaseInsensitiveString{StringValue: stringValue}, nil
+}
+
+type CaseInsensitiveString struct {
+	// The framework requires custom types extend a primitive or object.
+	basetypes.StringValue
+}
+
+var (
+	_ basetypes.StringValuable                   = CaseInsensitiveString{}
+	_ basetypes.StringValuableWithSemanticEquals = CaseInsensitiveString{}
+)
+
+func NewCaseInsensitiveStringNull() CaseInsensitiveString {
+	return CaseInsensitiveString{
+		StringValue: basetypes.NewStringNull(),
+	}
+}
+
+func NewCaseInsensitiveStringUnknown() CaseInsensitiveString {
+	return CaseInsensitiveString{
+		StringValue: basetypes.NewStringUnknown(),
+	}
+}
+
+func CaseInsensitiveStringValue(value string) CaseInsensitiveString {
+	return CaseInsensitiveString{
+		StringValue: basetypes.NewStringValue(value),
+	}
+}
+
+// Equal implements basetypes.StringValuable. Values that differ in case aren't
+// equal, so changing only the case of a value is still planned.
+func (v CaseInsensitiveString) Equal(o attr.Value) bool {
+	if o, ok := o.(CaseInsensitiveString); ok {
+		return v.StringValue.Equal(o.StringValue)
+	}
+	return false
+}
+
+// Type implements basetypes.StringValuable.
+func (v CaseInsensitiveString) Type(context.Context) attr.Type {
+	return CaseInsensitiveStringType
+}
+
+// StringSemanticEquals implements basetypes.StringValuableWithSemanticEquals.
+func (v CaseInsensitiveString) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(CaseInsensitiveString)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T. Please report this to the provider developers.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	return strings.EqualFold(v.ValueString(), newValue.ValueString()), diags
+}
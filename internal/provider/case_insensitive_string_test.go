@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseInsensitiveStringTypeValueFromTerraform(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	actual, err := CaseInsensitiveStringType.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, nil))
+	require.NoError(t, err)
+	require.Equal(t, NewCaseInsensitiveStringNull(), actual)
+
+	actual, err = CaseInsensitiveStringType.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+	require.NoError(t, err)
+	require.Equal(t, NewCaseInsensitiveStringUnknown(), actual)
+
+	actual, err = CaseInsensitiveStringType.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "Alice@Example.com"))
+	require.NoError(t, err)
+	require.Equal(t, CaseInsensitiveStringValue("Alice@Example.com"), actual)
+}
+
+func TestCaseInsensitiveStringSemanticEquals(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		current  string
+		new      string
+		expected bool
+	}{
+		{name: "Same", current: "alice@example.com", new: "alice@example.com", expected: true},
+		{name: "DifferentCase", current: "Alice@Example.com", new: "alice@example.com", expected: true},
+		{name: "Different", current: "alice@example.com", new: "bob@example.com", expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			equal, diags := CaseInsensitiveStringValue(c.current).StringSemanticEquals(ctx, CaseInsensitiveStringValue(c.new))
+			require.False(t, diags.HasError())
+			require.Equal(t, c.expected, equal)
+		})
+	}
+
+	// Only the case changing is still a change in the plan.
+	require.False(t, CaseInsensitiveStringValue("Alice").Equal(CaseInsensitiveStringValue("alice")))
+
+	_, diags := CaseInsensitiveStringValue("alice").StringSemanticEquals(ctx, types.StringValue("alice"))
+	require.True(t, diags.HasError())
+}
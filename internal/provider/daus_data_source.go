@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DAUsDataSource{}
+
+func NewDAUsDataSource() datasource.DataSource {
+	return &DAUsDataSource{}
+}
+
+// DAUsDataSource defines the data source implementation.
+type DAUsDataSource struct {
+	data *CoderdProviderData
+}
+
+// DAUsDataSourceModel describes the data source data model.
+type DAUsDataSourceModel struct {
+	// If null, deployment-wide DAUs are returned.
+	TemplateID   UUID        `tfsdk:"template_id"`
+	TZHourOffset types.Int64 `tfsdk:"tz_hour_offset"`
+
+	Entries []DAUsEntry `tfsdk:"entries"`
+}
+
+type DAUsEntry struct {
+	Date   types.String `tfsdk:"date"`
+	Amount types.Int64  `tfsdk:"amount"`
+}
+
+func (d *DAUsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_daus"
+}
+
+func (d *DAUsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Daily active user metrics for the Coder deployment, or for a single template.",
+
+		Attributes: map[string]schema.Attribute{
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template to retrieve daily active users for. If omitted, daily active users across the whole deployment are returned.",
+				CustomType:          UUIDType,
+				Optional:            true,
+			},
+			"tz_hour_offset": schema.Int64Attribute{
+				MarkdownDescription: "The timezone offset from UTC, in hours, used to bucket users into days. Defaults to 0.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(-12, 14),
+				},
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "Daily active user counts, ordered by date.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"date": schema.StringAttribute{
+							MarkdownDescription: "The date of the entry, formatted as `YYYY-MM-DD`.",
+							Computed:            true,
+						},
+						"amount": schema.Int64Attribute{
+							MarkdownDescription: "The number of unique users active on the date.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DAUsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *DAUsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DAUsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	if data.TZHourOffset.IsNull() {
+		data.TZHourOffset = types.Int64Value(0)
+	}
+	tzOffset := int(data.TZHourOffset.ValueInt64())
+
+	var (
+		daus *codersdk.DAUsResponse
+		err  error
+	)
+	if !data.TemplateID.IsNull() {
+		daus, err = client.TemplateDAUs(ctx, data.TemplateID.ValueUUID(), tzOffset)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get template daily active users, got error: %s", err))
+			return
+		}
+	} else {
+		daus, err = client.DeploymentDAUs(ctx, tzOffset)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get deployment daily active users, got error: %s", err))
+			return
+		}
+	}
+
+	entries := make([]DAUsEntry, 0, len(daus.Entries))
+	for _, entry := range daus.Entries {
+		entries = append(entries, DAUsEntry{
+			Date:   types.StringValue(entry.Date),
+			Amount: types.Int64Value(int64(entry.Amount)),
+		})
+	}
+	data.Entries = entries
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDAUsDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "daus_data_acc", false)
+
+	t.Run("DeploymentOk", func(t *testing.T) {
+		cfg := testAccDAUsDataSourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.coderd_daus.test", "tz_hour_offset", "0"),
+						resource.TestCheckResourceAttrSet("data.coderd_daus.test", "entries.#"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("TimezoneOffsetOk", func(t *testing.T) {
+		cfg := testAccDAUsDataSourceConfig{
+			URL:          client.URL.String(),
+			Token:        client.SessionToken(),
+			TZHourOffset: PtrTo(int64(-5)),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.coderd_daus.test", "tz_hour_offset", "-5"),
+						resource.TestCheckResourceAttrSet("data.coderd_daus.test", "entries.#"),
+					),
+				},
+			},
+		})
+	})
+}
+
+type testAccDAUsDataSourceConfig struct {
+	URL   string
+	Token string
+
+	TemplateID   *string
+	TZHourOffset *int64
+}
+
+func (c testAccDAUsDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_daus" "test" {
+	template_id    = {{orNull .TemplateID}}
+	tz_hour_offset = {{orNull .TZHourOffset}}
+}
+`
+
+	funcMap := template.FuncMap{
+		"orNull": PrintOrNull,
+	}
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("dausDataSource").Funcs(funcMap).Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
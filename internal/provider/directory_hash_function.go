@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DirectoryHashFunction{}
+
+func NewDirectoryHashFunction() function.Function {
+	return &DirectoryHashFunction{}
+}
+
+// DirectoryHashFunction defines the function implementation.
+type DirectoryHashFunction struct{}
+
+func (f *DirectoryHashFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "directory_hash"
+}
+
+func (f *DirectoryHashFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Hash the contents of a template directory",
+		MarkdownDescription: "Returns the hash of the files in a directory, as computed for the `directory_hash` of a `coderd_template` version. " +
+			"Files excluded by the directory's `.terraformignore` file, or by the given patterns, aren't hashed. " +
+			"Line endings are hashed as they are, as when `normalize_line_endings` is false.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "path",
+				MarkdownDescription: "The path to the directory.",
+			},
+			function.ListParameter{
+				Name:                "excludes",
+				MarkdownDescription: "Gitignore-style patterns of files to exclude, as in the `exclude` of a template version. May be null.",
+				ElementType:         types.StringType,
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DirectoryHashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var directory string
+	var excludes []string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &directory, &excludes))
+	if resp.Error != nil {
+		return
+	}
+
+	rules, err := loadIgnoreRules(directory, excludes)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("Failed to load exclude patterns: %s", err))
+		return
+	}
+	hash, err := computeDirectoryHash(directory, rules, false)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Failed to compute directory hash: %s", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, hash))
+}
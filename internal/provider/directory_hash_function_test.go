@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryHashFunction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource {}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Template"), 0o600))
+
+	run := func(t *testing.T, excludes types.List) (string, *function.FuncError) {
+		t.Helper()
+		resp := &function.RunResponse{
+			Result: function.NewResultData(types.StringUnknown()),
+		}
+		NewDirectoryHashFunction().Run(ctx, function.RunRequest{
+			Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(dir), excludes}),
+		}, resp)
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		return resp.Result.Value().(types.String).ValueString(), nil
+	}
+
+	t.Run("NoExcludes", func(t *testing.T) {
+		t.Parallel()
+		rules, err := loadIgnoreRules(dir, nil)
+		require.NoError(t, err)
+		expected, err := computeDirectoryHash(dir, rules, false)
+		require.NoError(t, err)
+		actual, funcErr := run(t, types.ListNull(types.StringType))
+		require.Nil(t, funcErr)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("Excludes", func(t *testing.T) {
+		t.Parallel()
+		rules, err := loadIgnoreRules(dir, []string{"*.md"})
+		require.NoError(t, err)
+		expected, err := computeDirectoryHash(dir, rules, false)
+		require.NoError(t, err)
+		excludes := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("*.md")})
+		actual, funcErr := run(t, excludes)
+		require.Nil(t, funcErr)
+		require.Equal(t, expected, actual)
+
+		unexcluded, funcErr := run(t, types.ListNull(types.StringType))
+		require.Nil(t, funcErr)
+		require.NotEqual(t, unexcluded, actual)
+	})
+}
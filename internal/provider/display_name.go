@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type displayNamePlanModifier struct{}
+
+// NewDisplayNamePlanModifier plans a display name that isn't configured as
+// the sibling `name` attribute, so the display name filled in when applying
+// doesn't show as a change in later plans.
+func NewDisplayNamePlanModifier() planmodifier.String {
+	return &displayNamePlanModifier{}
+}
+
+// Description implements planmodifier.String.
+func (m *displayNamePlanModifier) Description(ctx context.Context) string {
+	return m.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements planmodifier.String.
+func (m *displayNamePlanModifier) MarkdownDescription(context.Context) string {
+	return "Defaults to `name` when not configured."
+}
+
+// PlanModifyString implements planmodifier.String.
+func (m *displayNamePlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	var name types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, req.Path.ParentPath().AtName("name"), &name)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.PlanValue = defaultDisplayName(name, req.StateValue)
+}
+
+// defaultDisplayName returns the display name to plan when it isn't
+// configured. It's the name, unless the current display name is empty, which
+// Coder shows as the name anyway.
+func defaultDisplayName(name, current types.String) types.String {
+	if !current.IsNull() && !current.IsUnknown() && current.ValueString() == "" {
+		return current
+	}
+	if name.IsUnknown() {
+		return types.StringUnknown()
+	}
+	return name
+}
+
+var _ planmodifier.String = &displayNamePlanModifier{}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultDisplayName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		resource types.String
+		current  types.String
+		expected types.String
+	}{
+		{
+			name:     "Create",
+			resource: types.StringValue("example"),
+			current:  types.StringNull(),
+			expected: types.StringValue("example"),
+		},
+		{
+			name:     "FollowsName",
+			resource: types.StringValue("renamed"),
+			current:  types.StringValue("example"),
+			expected: types.StringValue("renamed"),
+		},
+		{
+			name:     "UnknownName",
+			resource: types.StringUnknown(),
+			current:  types.StringValue("example"),
+			expected: types.StringUnknown(),
+		},
+		{
+			name:     "KeepsEmpty",
+			resource: types.StringValue("example"),
+			current:  types.StringValue(""),
+			expected: types.StringValue(""),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.expected, defaultDisplayName(c.resource, c.current))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// formatMillis returns a Go duration string for the given number of
+// milliseconds, e.g. `1h30m0s`.
+func formatMillis(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// parseMillis parses a Go duration string into a number of milliseconds.
+func parseMillis(s string) (int64, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	return d.Milliseconds(), nil
+}
+
+// durationValueFromMillis returns a duration string for the given number of
+// milliseconds, preserving the current value if it represents the same
+// duration, so that e.g. `24h` isn't rewritten to `24h0m0s`.
+func durationValueFromMillis(current types.String, ms int64) types.String {
+	if !current.IsNull() && !current.IsUnknown() {
+		if cur, err := parseMillis(current.ValueString()); err == nil && cur == ms {
+			return current
+		}
+	}
+	return types.StringValue(formatMillis(ms))
+}
+
+type durationValidator struct{}
+
+// NewDurationValidator validates that a string is a non-negative Go duration,
+// e.g. `24h` or `1h30m`.
+func NewDurationValidator() validator.String {
+	return &durationValidator{}
+}
+
+// Description implements validator.String.
+func (d *durationValidator) Description(ctx context.Context) string {
+	return d.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements validator.String.
+func (d *durationValidator) MarkdownDescription(context.Context) string {
+	return "Validate that the value is a non-negative duration, such as `24h` or `1h30m`."
+}
+
+// ValidateString implements validator.String.
+func (d *durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	dur, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration",
+			fmt.Sprintf("Value must be a duration such as \"24h\" or \"1h30m\", got %q: %s", req.ConfigValue.ValueString(), err))
+		return
+	}
+	if dur < 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration",
+			fmt.Sprintf("Value must not be negative, got %q", req.ConfigValue.ValueString()))
+	}
+}
+
+var _ validator.String = &durationValidator{}
+
+type durationFromMillisPlanModifier struct {
+	millisAttr    string
+	defaultMillis int64
+}
+
+// NewDurationFromMillisPlanModifier plans a duration attribute that isn't
+// configured from its sibling milliseconds attribute, falling back to
+// defaultMillis if neither is configured.
+func NewDurationFromMillisPlanModifier(millisAttr string, defaultMillis int64) planmodifier.String {
+	return &durationFromMillisPlanModifier{
+		millisAttr:    millisAttr,
+		defaultMillis: defaultMillis,
+	}
+}
+
+// Description implements planmodifier.String.
+func (d *durationFromMillisPlanModifier) Description(ctx context.Context) string {
+	return d.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements planmodifier.String.
+func (d *durationFromMillisPlanModifier) MarkdownDescription(context.Context) string {
+	return fmt.Sprintf("Compute the duration from `%s` when not configured.", d.millisAttr)
+}
+
+// PlanModifyString implements planmodifier.String.
+func (d *durationFromMillisPlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	var millis types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName(d.millisAttr), &millis)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch {
+	case millis.IsUnknown():
+		resp.PlanValue = types.StringUnknown()
+	case millis.IsNull():
+		resp.PlanValue = durationValueFromMillis(req.StateValue, d.defaultMillis)
+	default:
+		resp.PlanValue = durationValueFromMillis(req.StateValue, millis.ValueInt64())
+	}
+}
+
+var _ planmodifier.String = &durationFromMillisPlanModifier{}
+
+type millisFromDurationPlanModifier struct {
+	durationAttr string
+}
+
+// NewMillisFromDurationPlanModifier plans a milliseconds attribute that isn't
+// configured from its sibling duration attribute, if that is configured.
+func NewMillisFromDurationPlanModifier(durationAttr string) planmodifier.Int64 {
+	return &millisFromDurationPlanModifier{
+		durationAttr: durationAttr,
+	}
+}
+
+// Description implements planmodifier.Int64.
+func (m *millisFromDurationPlanModifier) Description(ctx context.Context) string {
+	return m.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements planmodifier.Int64.
+func (m *millisFromDurationPlanModifier) MarkdownDescription(context.Context) string {
+	return fmt.Sprintf("Compute the milliseconds from `%s` when configured.", m.durationAttr)
+}
+
+// PlanModifyInt64 implements planmodifier.Int64.
+func (m *millisFromDurationPlanModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	var duration types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName(m.durationAttr), &duration)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch {
+	case duration.IsUnknown():
+		resp.PlanValue = types.Int64Unknown()
+	case duration.IsNull():
+		return
+	default:
+		ms, err := parseMillis(duration.ValueString())
+		if err != nil {
+			// Reported by the validator on the duration attribute.
+			return
+		}
+		resp.PlanValue = types.Int64Value(ms)
+	}
+}
+
+var _ planmodifier.Int64 = &millisFromDurationPlanModifier{}
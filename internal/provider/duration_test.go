@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationValueFromMillis(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		current  types.String
+		ms       int64
+		expected types.String
+	}{
+		{
+			name:     "Null",
+			current:  types.StringNull(),
+			ms:       90 * 60 * 1000,
+			expected: types.StringValue("1h30m0s"),
+		},
+		{
+			name:     "Unknown",
+			current:  types.StringUnknown(),
+			ms:       0,
+			expected: types.StringValue("0s"),
+		},
+		{
+			name:     "EquivalentPreserved",
+			current:  types.StringValue("24h"),
+			ms:       24 * 60 * 60 * 1000,
+			expected: types.StringValue("24h"),
+		},
+		{
+			name:     "Changed",
+			current:  types.StringValue("24h"),
+			ms:       60 * 1000,
+			expected: types.StringValue("1m0s"),
+		},
+		{
+			name:     "Invalid",
+			current:  types.StringValue("one day"),
+			ms:       1000,
+			expected: types.StringValue("1s"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.expected, durationValueFromMillis(c.current, c.ms))
+		})
+	}
+}
+
+func TestParseMillis(t *testing.T) {
+	t.Parallel()
+	ms, err := parseMillis("1h30m")
+	require.NoError(t, err)
+	require.EqualValues(t, 90*60*1000, ms)
+
+	ms, err = parseMillis("1500ms")
+	require.NoError(t, err)
+	require.EqualValues(t, 1500, ms)
+
+	_, err = parseMillis("1d")
+	require.Error(t, err)
+}
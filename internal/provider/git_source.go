@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// GitSource is a remote Git repository to create a template version from.
+type GitSource struct {
+	URL          types.String `tfsdk:"url"`
+	Ref          types.String `tfsdk:"ref"`
+	Subdirectory types.String `tfsdk:"subdirectory"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	Commit       types.String `tfsdk:"commit"`
+}
+
+var gitSourceAttribute = schema.SingleNestedAttribute{
+	MarkdownDescription: "A remote Git repository to create the template version from, instead of a local `directory`. " +
+		"The repository is cloned using the `git` executable on the machine running Terraform, " +
+		"so SSH URLs use the local SSH agent and configuration. " +
+		"Changes in the contents of the repository at `ref` will trigger the creation of a new template version.",
+	Optional: true,
+	Attributes: map[string]schema.Attribute{
+		"url": schema.StringAttribute{
+			MarkdownDescription: "The URL of the repository, e.g. `https://github.com/coder/templates.git`.",
+			Required:            true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+		"ref": schema.StringAttribute{
+			MarkdownDescription: "The branch, tag or commit SHA to check out. Defaults to the default branch of the repository.",
+			Optional:            true,
+		},
+		"subdirectory": schema.StringAttribute{
+			MarkdownDescription: "The path of the template within the repository. Defaults to the root of the repository.",
+			Optional:            true,
+		},
+		"username": schema.StringAttribute{
+			MarkdownDescription: "The username to authenticate to the repository with over HTTPS.",
+			Optional:            true,
+			Validators: []validator.String{
+				stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("password")),
+			},
+		},
+		"password": schema.StringAttribute{
+			MarkdownDescription: "The password or access token to authenticate to the repository with over HTTPS. It's saved into the state as plain text, as write-only attributes aren't supported.",
+			Optional:            true,
+			Sensitive:           true,
+			Validators: []validator.String{
+				stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("username")),
+			},
+		},
+		"commit": schema.StringAttribute{
+			MarkdownDescription: "The commit SHA that `ref` resolved to when planning. The version is created from this commit, even if `ref` has moved since.",
+			Computed:            true,
+		},
+	},
+}
+
+// sourceDirectory returns the directory containing the contents of the
+// template version. For Git sources, the repository is cloned into a temporary
+// directory, which is removed by calling cleanup.
+func (v *TemplateVersion) sourceDirectory(ctx context.Context) (dir string, cleanup func(), err error) {
+	switch {
+	case v.Git != nil:
+		return cloneGitSource(ctx, v.Git)
+	case v.Archive != nil:
+		return extractArchiveSource(ctx, v.Archive)
+	default:
+		return v.Directory.ValueString(), func() {}, nil
+	}
+}
+
+// sourceUnknown returns true if the location of the template version contents
+// is not yet known.
+func (v *TemplateVersion) sourceUnknown() bool {
+	switch {
+	case v.Git != nil:
+		return v.Git.URL.IsUnknown() || v.Git.Ref.IsUnknown() || v.Git.Subdirectory.IsUnknown() ||
+			v.Git.Username.IsUnknown() || v.Git.Password.IsUnknown()
+	case v.Archive != nil:
+		return v.Archive.Path.IsUnknown() || v.Archive.URL.IsUnknown() || v.Archive.SHA256.IsUnknown()
+	default:
+		return v.Directory.IsUnknown()
+	}
+}
+
+// cloneGitSource shallow clones the repository into a temporary directory,
+// and returns the path of the requested subdirectory. If the commit is known,
+// it's checked out, otherwise the ref is, and the commit is set to the commit
+// SHA the ref resolved to, so the same commit is checked out when applying.
+func cloneGitSource(ctx context.Context, src *GitSource) (dir string, cleanup func(), err error) {
+	subdir := src.Subdirectory.ValueString()
+	if subdir != "" && !filepath.IsLocal(subdir) {
+		return "", nil, fmt.Errorf("subdirectory %q must be a relative path within the repository", subdir)
+	}
+	ref := src.Ref.ValueString()
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if src.Commit.ValueString() != "" {
+		ref = src.Commit.ValueString()
+	}
+
+	tmpDir, err := os.MkdirTemp("", "coderd-git-source-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup = func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if !src.Username.IsNull() {
+		// Pass credentials through the environment, so they are not visible in
+		// the process list or persisted to the repository's config.
+		auth := base64.StdEncoding.EncodeToString([]byte(src.Username.ValueString() + ":" + src.Password.ValueString()))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+	git := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpDir
+		cmd.Env = env
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	tflog.Info(ctx, "cloning git repository", map[string]any{
+		"url": src.URL.ValueString(),
+		"ref": ref,
+	})
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", src.URL.ValueString()},
+		{"fetch", "--quiet", "--depth", "1", "origin", ref},
+		{"checkout", "--quiet", "--detach", "FETCH_HEAD"},
+	} {
+		if _, err := git(args...); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	commit, err := git("rev-parse", "HEAD")
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	src.Commit = types.StringValue(commit)
+	// The repository metadata is not part of the template, and would
+	// otherwise change the directory hash on every clone.
+	if err := os.RemoveAll(filepath.Join(tmpDir, ".git")); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to remove repository metadata: %w", err)
+	}
+
+	dir = filepath.Join(tmpDir, subdir)
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		cleanup()
+		return "", nil, fmt.Errorf("subdirectory %q does not exist in the repository at %q", subdir, ref)
+	}
+	tflog.Info(ctx, "successfully cloned git repository")
+	return dir, cleanup, nil
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneGitSource(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ctx := context.Background()
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@coder.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@coder.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "--quiet", "--initial-branch", "main")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "templates", "docker"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "templates", "docker", "main.tf"), []byte("# v1"), 0o600))
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	v1 := git("rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "templates", "docker", "main.tf"), []byte("# v2"), 0o600))
+	git("commit", "--quiet", "-am", "v2")
+	url := "file://" + repo
+
+	t.Run("DefaultBranch", func(t *testing.T) {
+		t.Parallel()
+		dir, cleanup, err := cloneGitSource(ctx, &GitSource{
+			URL:          types.StringValue(url),
+			Subdirectory: types.StringValue("templates/docker"),
+		})
+		require.NoError(t, err)
+		defer cleanup()
+		content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+		require.NoError(t, err)
+		require.Equal(t, "# v2", string(content))
+		require.NoDirExists(t, filepath.Join(dir, "..", "..", ".git"))
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		t.Parallel()
+		src := &GitSource{
+			URL:          types.StringValue(url),
+			Ref:          types.StringValue("v1"),
+			Subdirectory: types.StringValue("templates/docker"),
+		}
+		dir, cleanup, err := cloneGitSource(ctx, src)
+		require.NoError(t, err)
+		defer cleanup()
+		content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+		require.NoError(t, err)
+		require.Equal(t, "# v1", string(content))
+		require.Equal(t, v1, src.Commit.ValueString())
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		t.Parallel()
+		// The commit resolved when planning is checked out, even though the
+		// branch has moved since.
+		dir, cleanup, err := cloneGitSource(ctx, &GitSource{
+			URL:          types.StringValue(url),
+			Ref:          types.StringValue("main"),
+			Subdirectory: types.StringValue("templates/docker"),
+			Commit:       types.StringValue(v1),
+		})
+		require.NoError(t, err)
+		defer cleanup()
+		content, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+		require.NoError(t, err)
+		require.Equal(t, "# v1", string(content))
+	})
+
+	t.Run("CleanupRemovesClone", func(t *testing.T) {
+		t.Parallel()
+		dir, cleanup, err := cloneGitSource(ctx, &GitSource{
+			URL: types.StringValue(url),
+		})
+		require.NoError(t, err)
+		require.DirExists(t, dir)
+		cleanup()
+		require.NoDirExists(t, dir)
+	})
+
+	t.Run("MissingSubdirectory", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := cloneGitSource(ctx, &GitSource{
+			URL:          types.StringValue(url),
+			Subdirectory: types.StringValue("templates/kubernetes"),
+		})
+		require.ErrorContains(t, err, "does not exist")
+	})
+
+	t.Run("SubdirectoryOutsideRepository", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := cloneGitSource(ctx, &GitSource{
+			URL:          types.StringValue(url),
+			Subdirectory: types.StringValue("../"),
+		})
+		require.ErrorContains(t, err, "must be a relative path")
+	})
+
+	t.Run("MissingRef", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := cloneGitSource(ctx, &GitSource{
+			URL: types.StringValue(url),
+			Ref: types.StringValue("does-not-exist"),
+		})
+		require.ErrorContains(t, err, "git fetch")
+	})
+}
@@ -32,11 +32,12 @@ type GroupDataSourceModel struct {
 	Name           types.String `tfsdk:"name"`
 	OrganizationID UUID         `tfsdk:"organization_id"`
 
-	DisplayName    types.String `tfsdk:"display_name"`
-	AvatarURL      types.String `tfsdk:"avatar_url"`
-	QuotaAllowance types.Int32  `tfsdk:"quota_allowance"`
-	Source         types.String `tfsdk:"source"`
-	Members        []Member     `tfsdk:"members"`
+	DisplayName         types.String `tfsdk:"display_name"`
+	AvatarURL           types.String `tfsdk:"avatar_url"`
+	QuotaAllowance      types.Int32  `tfsdk:"quota_allowance"`
+	TotalQuotaAllowance types.Int64  `tfsdk:"total_quota_allowance"`
+	Source              types.String `tfsdk:"source"`
+	Members             []Member     `tfsdk:"members"`
 }
 
 type Member struct {
@@ -92,6 +93,10 @@ func (d *GroupDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 				MarkdownDescription: "The number of quota credits to allocate to each user in the group.",
 				Computed:            true,
 			},
+			"total_quota_allowance": schema.Int64Attribute{
+				MarkdownDescription: "The number of quota credits the group adds to the budgets of its members in total, which is `quota_allowance` for each member.",
+				Computed:            true,
+			},
 			"source": schema.StringAttribute{
 				MarkdownDescription: "The source of the group. Either `oidc` or `user`.",
 				Computed:            true,
@@ -193,7 +198,7 @@ func (d *GroupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		data.Name = types.StringValue(group.Name)
 		data.OrganizationID = UUIDValue(group.OrganizationID)
 	} else {
-		group, err = client.GroupByOrgAndName(ctx, data.OrganizationID.ValueUUID(), data.Name.ValueString())
+		group, err = d.data.groupByName(ctx, data.OrganizationID.ValueUUID(), data.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("Failed to get group by name and org ID", err.Error())
 			return
@@ -204,6 +209,7 @@ func (d *GroupDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	data.DisplayName = types.StringValue(group.DisplayName)
 	data.AvatarURL = types.StringValue(group.AvatarURL)
 	data.QuotaAllowance = types.Int32Value(int32(group.QuotaAllowance))
+	data.TotalQuotaAllowance = totalQuotaAllowance(group)
 	members := make([]Member, 0, len(group.Members))
 	for _, member := range group.Members {
 		members = append(members, Member{
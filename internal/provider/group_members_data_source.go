@@ -0,0 +1,209 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GroupMembersDataSource{}
+
+func NewGroupMembersDataSource() datasource.DataSource {
+	return &GroupMembersDataSource{}
+}
+
+// GroupMembersDataSource defines the data source implementation.
+type GroupMembersDataSource struct {
+	data *CoderdProviderData
+}
+
+// GroupMembersDataSourceModel describes the data source data model.
+type GroupMembersDataSourceModel struct {
+	GroupID UUID        `tfsdk:"group_id"`
+	Offset  types.Int64 `tfsdk:"offset"`
+	Limit   types.Int64 `tfsdk:"limit"`
+
+	TotalCount types.Int64 `tfsdk:"total_count"`
+	Members    []Member    `tfsdk:"members"`
+}
+
+func (d *GroupMembersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+func (d *GroupMembersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The members of an existing group on the Coder deployment, ordered by username.\n\n" +
+			"Use `offset` and `limit` to split very large groups into pages. The first page is " +
+			"retrieved with an `offset` of `0`, and subsequent pages by incrementing `offset` by `limit` until `total_count` is reached. " +
+			"The Coder API doesn't paginate group members, so each page still retrieves every member of the group from the deployment; " +
+			"pages only limit the number of members stored in the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"group_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the group to retrieve the members of.",
+				CustomType:          UUIDType,
+				Required:            true,
+			},
+			"offset": schema.Int64Attribute{
+				MarkdownDescription: "The number of members to skip. Defaults to `0`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of members to return. Defaults to `0`, which returns all remaining members.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"total_count": schema.Int64Attribute{
+				MarkdownDescription: "The total number of members in the group.",
+				Computed:            true,
+			},
+			"members": schema.ListNestedAttribute{
+				MarkdownDescription: "The members of the group in the requested page.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the member.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "The username of the member.",
+							Computed:            true,
+						},
+						"email": schema.StringAttribute{
+							MarkdownDescription: "The email of the member.",
+							Computed:            true,
+						},
+						"created_at": schema.Int64Attribute{
+							MarkdownDescription: "Unix timestamp of when the member was created.",
+							Computed:            true,
+						},
+						"last_seen_at": schema.Int64Attribute{
+							MarkdownDescription: "Unix timestamp of when the member was last seen.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The status of the member. Can be `active`, `dormant` or `suspended`.",
+							Computed:            true,
+						},
+						"login_type": schema.StringAttribute{
+							MarkdownDescription: "The login type of the member. Can be `oidc`, `token`, `password`, `github` or `none`.",
+							Computed:            true,
+						},
+						"theme_preference": schema.StringAttribute{
+							MarkdownDescription: "The member's preferred theme.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *GroupMembersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *GroupMembersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GroupMembersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(CheckGroupEntitlements(ctx, d.data.Features)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	if data.Offset.IsNull() {
+		data.Offset = types.Int64Value(0)
+	}
+	if data.Limit.IsNull() {
+		data.Limit = types.Int64Value(0)
+	}
+
+	group, err := client.Group(ctx, data.GroupID.ValueUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get group by ID, got error: %s", err))
+		return
+	}
+
+	page := pageMembers(group.Members, int(data.Offset.ValueInt64()), int(data.Limit.ValueInt64()))
+	members := make([]Member, 0, len(page))
+	for _, member := range page {
+		members = append(members, Member{
+			ID:              UUIDValue(member.ID),
+			Username:        types.StringValue(member.Username),
+			Email:           types.StringValue(member.Email),
+			CreatedAt:       types.Int64Value(member.CreatedAt.Unix()),
+			LastSeenAt:      types.Int64Value(member.LastSeenAt.Unix()),
+			Status:          types.StringValue(string(member.Status)),
+			LoginType:       types.StringValue(string(member.LoginType)),
+			ThemePreference: types.StringValue(member.ThemePreference),
+		})
+	}
+	data.Members = members
+	data.TotalCount = types.Int64Value(int64(len(group.Members)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// pageMembers sorts members by username, and returns at most limit of them
+// starting at offset. A limit of zero returns all remaining members. The
+// members are paged locally, as the API returns every member of a group.
+func pageMembers(members []codersdk.ReducedUser, offset, limit int) []codersdk.ReducedUser {
+	sorted := slices.Clone(members)
+	slices.SortFunc(sorted, func(a, b codersdk.ReducedUser) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+	if offset >= len(sorted) {
+		return nil
+	}
+	sorted = sorted[offset:]
+	if limit > 0 && limit < len(sorted) {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
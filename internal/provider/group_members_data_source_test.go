@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccGroupMembersDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "group_members_data_acc", true)
+	firstUser, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	group, err := client.CreateGroup(ctx, firstUser.OrganizationIDs[0], codersdk.CreateGroupRequest{
+		Name: "example-group",
+	})
+	require.NoError(t, err)
+
+	userIDs := make([]string, 0, 3)
+	for _, username := range []string{"charlie", "alice", "bob"} {
+		user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+			Email:          username + "@coder.com",
+			Username:       username,
+			Password:       "SomeSecurePassword!",
+			UserLoginType:  "password",
+			OrganizationID: firstUser.OrganizationIDs[0],
+		})
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID.String())
+	}
+	_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+		AddUsers: userIDs,
+	})
+	require.NoError(t, err)
+
+	t.Run("AllMembersOk", func(t *testing.T) {
+		cfg := testAccGroupMembersDataSourceConfig{
+			URL:     client.URL.String(),
+			Token:   client.SessionToken(),
+			GroupID: group.ID.String(),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "total_count", "3"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.#", "3"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.0.username", "alice"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.0.email", "alice@coder.com"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.2.username", "charlie"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("PageOk", func(t *testing.T) {
+		cfg := testAccGroupMembersDataSourceConfig{
+			URL:     client.URL.String(),
+			Token:   client.SessionToken(),
+			GroupID: group.ID.String(),
+			Offset:  PtrTo(int64(1)),
+			Limit:   PtrTo(int64(1)),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "total_count", "3"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.#", "1"),
+						resource.TestCheckResourceAttr("data.coderd_group_members.test", "members.0.username", "bob"),
+					),
+				},
+			},
+		})
+	})
+}
+
+func TestPageMembers(t *testing.T) {
+	t.Parallel()
+	members := []codersdk.ReducedUser{
+		{MinimalUser: codersdk.MinimalUser{Username: "charlie"}},
+		{MinimalUser: codersdk.MinimalUser{Username: "alice"}},
+		{MinimalUser: codersdk.MinimalUser{Username: "bob"}},
+	}
+	usernames := func(members []codersdk.ReducedUser) []string {
+		names := make([]string, 0, len(members))
+		for _, member := range members {
+			names = append(names, member.Username)
+		}
+		return names
+	}
+
+	cases := []struct {
+		Name     string
+		Offset   int
+		Limit    int
+		Expected []string
+	}{
+		{Name: "All", Offset: 0, Limit: 0, Expected: []string{"alice", "bob", "charlie"}},
+		{Name: "FirstPage", Offset: 0, Limit: 2, Expected: []string{"alice", "bob"}},
+		{Name: "LastPage", Offset: 2, Limit: 2, Expected: []string{"charlie"}},
+		{Name: "Remaining", Offset: 1, Limit: 0, Expected: []string{"bob", "charlie"}},
+		{Name: "PastEnd", Offset: 3, Limit: 2, Expected: []string{}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, c.Expected, usernames(pageMembers(members, c.Offset, c.Limit)))
+		})
+	}
+	// The input is not reordered
+	require.Equal(t, []string{"charlie", "alice", "bob"}, usernames(members))
+}
+
+type testAccGroupMembersDataSourceConfig struct {
+	URL   string
+	Token string
+
+	GroupID string
+	Offset  *int64
+	Limit   *int64
+}
+
+func (c testAccGroupMembersDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_group_members" "test" {
+	group_id = "{{.GroupID}}"
+	offset   = {{orNull .Offset}}
+	limit    = {{orNull .Limit}}
+}
+`
+
+	funcMap := template.FuncMap{
+		"orNull": PrintOrNull,
+	}
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("groupMembersDataSource").Funcs(funcMap).Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -2,17 +2,22 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -25,6 +30,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &GroupResource{}
 var _ resource.ResourceWithImportState = &GroupResource{}
+var _ resource.ResourceWithModifyPlan = &GroupResource{}
+var _ resource.ResourceWithUpgradeState = &GroupResource{}
 
 func NewGroupResource() resource.Resource {
 	return &GroupResource{}
@@ -39,12 +46,182 @@ type GroupResource struct {
 type GroupResourceModel struct {
 	ID UUID `tfsdk:"id"`
 
-	Name           types.String `tfsdk:"name"`
-	DisplayName    types.String `tfsdk:"display_name"`
-	AvatarURL      types.String `tfsdk:"avatar_url"`
-	QuotaAllowance types.Int32  `tfsdk:"quota_allowance"`
-	OrganizationID UUID         `tfsdk:"organization_id"`
-	Members        types.Set    `tfsdk:"members"`
+	Name                types.String `tfsdk:"name"`
+	DisplayName         types.String `tfsdk:"display_name"`
+	AvatarURL           types.String `tfsdk:"avatar_url"`
+	AvatarFile          types.String `tfsdk:"avatar_file"`
+	QuotaAllowance      types.Int32  `tfsdk:"quota_allowance"`
+	TotalQuotaAllowance types.Int64  `tfsdk:"total_quota_allowance"`
+	OrganizationID      UUID         `tfsdk:"organization_id"`
+	Members             types.Set    `tfsdk:"members"`
+	MembershipMode      types.String `tfsdk:"membership_mode"`
+	Source              types.String `tfsdk:"source"`
+	AdoptOIDC           types.Bool   `tfsdk:"adopt_oidc"`
+
+	MemberSourceGroupIDs types.Set `tfsdk:"member_source_group_ids"`
+	SourceMembers        types.Set `tfsdk:"source_members"`
+}
+
+const (
+	groupMembershipExact    = "exact"
+	groupMembershipAdditive = "additive"
+)
+
+// managesMembers returns whether Terraform manages the members of the group.
+func (m *GroupResourceModel) managesMembers() bool {
+	return !m.Members.IsNull() || !m.MemberSourceGroupIDs.IsNull()
+}
+
+// managedMembers returns the members in either members or source_members.
+func (m *GroupResourceModel) managedMembers(ctx context.Context) ([]UUID, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var result []UUID
+	seen := map[uuid.UUID]struct{}{}
+	for _, set := range []types.Set{m.Members, m.SourceMembers} {
+		if set.IsNull() || set.IsUnknown() {
+			continue
+		}
+		var ids []UUID
+		diags.Append(set.ElementsAs(ctx, &ids, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		for _, id := range ids {
+			if _, ok := seen[id.ValueUUID()]; ok {
+				continue
+			}
+			seen[id.ValueUUID()] = struct{}{}
+			result = append(result, id)
+		}
+	}
+	return result, diags
+}
+
+// memberChanges returns the members to add to and remove from the group to
+// match the planned members. If members are unmanaged, there are none. In
+// additive mode, only members managed in the prior state are removed.
+func (m *GroupResourceModel) memberChanges(ctx context.Context, group codersdk.Group, prior *GroupResourceModel) (add, remove []string, diags diag.Diagnostics) {
+	if !m.managesMembers() {
+		return nil, nil, diags
+	}
+	plannedMembers, diags := m.managedMembers(ctx)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	curMembers := make([]uuid.UUID, 0, len(group.Members))
+	for _, member := range group.Members {
+		curMembers = append(curMembers, member.ID)
+	}
+	if m.MembershipMode.ValueString() != groupMembershipAdditive {
+		add, remove = memberDiff(curMembers, plannedMembers)
+		return add, remove, diags
+	}
+
+	var managed []UUID
+	if prior != nil {
+		var priorDiags diag.Diagnostics
+		managed, priorDiags = prior.managedMembers(ctx)
+		diags.Append(priorDiags...)
+		if diags.HasError() {
+			return nil, nil, diags
+		}
+	}
+	add, _ = memberDiff(curMembers, plannedMembers)
+	_, remove = memberDiff(intersectMembers(curMembers, managed), plannedMembers)
+	return add, remove, diags
+}
+
+// patchMembers adds and removes group members in batches, so large changes
+// don't fail as a single request. Batches that succeed are kept if others
+// fail.
+func (r *GroupResource) patchMembers(ctx context.Context, groupID uuid.UUID, add, remove []string) error {
+	batches := memberBatches(add, remove, r.data.GroupMemberBatchSize)
+	if len(batches) == 0 {
+		return nil
+	}
+	concurrency := max(r.data.GroupMemberConcurrency, 1)
+	tflog.Info(ctx, "patching group members", map[string]any{
+		"id":          groupID,
+		"batches":     len(batches),
+		"concurrency": concurrency,
+	})
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, err := r.data.Client.PatchGroup(ctx, groupID, batch)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d batches failed: %w", len(errs), len(batches), errors.Join(errs...))
+	}
+	return nil
+}
+
+// memberBatches splits member changes into requests of at most size changes.
+// If size isn't positive, all the changes are made in one request.
+func memberBatches(add, remove []string, size int) []codersdk.PatchGroupRequest {
+	total := len(add) + len(remove)
+	if total == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = total
+	}
+	var batches []codersdk.PatchGroupRequest
+	var batch codersdk.PatchGroupRequest
+	count := 0
+	flush := func() {
+		batches = append(batches, batch)
+		batch = codersdk.PatchGroupRequest{}
+		count = 0
+	}
+	for _, id := range add {
+		batch.AddUsers = append(batch.AddUsers, id)
+		if count++; count == size {
+			flush()
+		}
+	}
+	for _, id := range remove {
+		batch.RemoveUsers = append(batch.RemoveUsers, id)
+		if count++; count == size {
+			flush()
+		}
+	}
+	if count > 0 {
+		flush()
+	}
+	return batches
+}
+
+// intersectMembers returns the members that are also in other.
+func intersectMembers(members []uuid.UUID, other []UUID) []uuid.UUID {
+	set := make(map[uuid.UUID]struct{}, len(other))
+	for _, id := range other {
+		set[id.ValueUUID()] = struct{}{}
+	}
+	var result []uuid.UUID
+	for _, id := range members {
+		if _, ok := set[id]; ok {
+			result = append(result, id)
+		}
+	}
+	return result
 }
 
 func CheckGroupEntitlements(ctx context.Context, features map[codersdk.FeatureName]codersdk.Feature) (diags diag.Diagnostics) {
@@ -61,9 +238,11 @@ func (r *GroupResource) Metadata(ctx context.Context, req resource.MetadataReque
 
 func (r *GroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: schemaVersion,
 		MarkdownDescription: "A group on the Coder deployment.\n\n" +
 			"Creating groups requires an Enterprise license.\n\n" +
-			"When importing, the ID supplied can be either a group UUID retrieved via the API or `<organization-name>/<group-name>`.",
+			"When importing, the ID supplied can be either a group UUID retrieved via the API or `<organization-name>/<group-name>`. " +
+			"The group's current members are imported into `members`, unless it was created by OIDC group sync.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -90,35 +269,92 @@ func (r *GroupResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringvalidator.LengthBetween(1, 64),
 					stringvalidator.RegexMatches(displayNameRegex, "Group display names must be alphanumeric with spaces"),
 				},
-				Default: stringdefault.StaticString(""),
+				PlanModifiers: []planmodifier.String{
+					NewDisplayNamePlanModifier(),
+				},
 			},
 			"avatar_url": schema.StringAttribute{
-				MarkdownDescription: "The URL of the group's avatar.",
+				MarkdownDescription: "The URL of the group's avatar. Must be an https URL, an image data URL, a path to a built-in emoji or icon (e.g. `/emojis/1f680.png` or `/icon/code.svg`), or an emoji shortcode.",
 				Computed:            true,
 				Optional:            true,
 				Default:             stringdefault.StaticString(""),
+				Validators: []validator.String{
+					NewIconURLValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					NewIconFilePlanModifier("avatar_file"),
+				},
+			},
+			"avatar_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a local image file to use as the group's avatar, which is embedded in `avatar_url` as a data URL. Changes in the file contents will update the avatar. The file must be no larger than 256 KiB. Conflicts with `avatar_url`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("avatar_url")),
+				},
 			},
 			// Int32 in the db
 			"quota_allowance": schema.Int32Attribute{
-				MarkdownDescription: "The number of quota credits to allocate to each user in the group.",
+				MarkdownDescription: "The number of quota credits to allocate to each user in the group. Must not be negative.",
 				Optional:            true,
 				Computed:            true,
 				Default:             int32default.StaticInt32(0),
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"total_quota_allowance": schema.Int64Attribute{
+				MarkdownDescription: "The number of quota credits the group adds to the budgets of its members in total, which is `quota_allowance` for each member, including members not managed by Terraform. " +
+					"A user's quota budget is the sum of the allowances of the groups they're in within the organization, including the `Everyone` group.",
+				Computed: true,
 			},
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "The organization ID that the group belongs to. Defaults to the provider default organization ID.",
+				MarkdownDescription: "The organization ID that the group belongs to. Defaults to the provider default organization ID. Changing the organization replaces the group, including unsetting it when the provider's default organization is configured and differs from the current one.",
 				CustomType:          UUIDType,
 				Optional:            true,
 				Computed:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplaceIfConfigured(),
-				},
 			},
 			"members": schema.SetAttribute{
 				MarkdownDescription: "Members of the group, by ID. If `null`, members will not be added or removed by Terraform. To have a group resource with unmanaged members, but be able to read the members in Terraform, use `data.coderd_group`",
 				ElementType:         UUIDType,
 				Optional:            true,
 			},
+			"membership_mode": schema.StringAttribute{
+				MarkdownDescription: "How `members` are managed. With `exact`, members not in `members` are removed from the group. " +
+					"With `additive`, Terraform only ensures the listed members are in the group, and never removes other members, such as those added by IdP sync or by an admin. " +
+					"Members removed from `members` are still removed from the group. Defaults to `exact`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(groupMembershipExact),
+				Validators: []validator.String{
+					stringvalidator.OneOf(groupMembershipExact, groupMembershipAdditive),
+				},
+			},
+			"member_source_group_ids": schema.SetAttribute{
+				MarkdownDescription: "IDs of groups whose members are also members of this group, approximating nested groups. " +
+					"The members of these groups are read when planning, so changes to their membership are applied by the next `terraform apply`. " +
+					"Members not in `members` or in these groups are removed from the group, unless `membership_mode` is `additive`.",
+				ElementType: UUIDType,
+				Optional:    true,
+			},
+			"source_members": schema.SetAttribute{
+				MarkdownDescription: "The members of the groups in `member_source_group_ids` that are members of this group.",
+				ElementType:         UUIDType,
+				Computed:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "How the group was created. Either `user`, or `oidc` for groups created by OIDC group sync.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"adopt_oidc": schema.BoolAttribute{
+				MarkdownDescription: "Whether to manage a group created by OIDC group sync. If set, creating the resource adopts an existing OIDC group with the same name instead of creating a new group, and OIDC groups can be imported. " +
+					"The group remains an OIDC group, so Coder continues to sync its members when users log in, and `members` should usually be `null`. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 		},
 	}
 }
@@ -164,14 +400,52 @@ func (r *GroupResource) Create(ctx context.Context, req resource.CreateRequest,
 		data.OrganizationID = UUIDValue(r.data.DefaultOrganizationID)
 	}
 
+	if data.DisplayName.IsUnknown() {
+		data.DisplayName = data.Name
+	}
+
 	orgID := data.OrganizationID.ValueUUID()
 
+	if data.SourceMembers.IsUnknown() {
+		var diags diag.Diagnostics
+		data.SourceMembers, diags = r.sourceGroupMembers(ctx, data.MemberSourceGroupIDs)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Groups looked up by name are out of date once the group is changed.
+	defer r.data.forgetGroups()
+	if data.AdoptOIDC.ValueBool() {
+		group, err := client.GroupByOrgAndName(ctx, orgID, data.Name.ValueString())
+		if err == nil && group.Source == codersdk.GroupSourceOIDC {
+			r.adoptGroup(ctx, &data, group, resp)
+			return
+		}
+	}
+
 	tflog.Info(ctx, "creating group")
-	group, err := client.CreateGroup(ctx, orgID, codersdk.CreateGroupRequest{
-		Name:           data.Name.ValueString(),
-		DisplayName:    data.DisplayName.ValueString(),
-		AvatarURL:      data.AvatarURL.ValueString(),
-		QuotaAllowance: int(data.QuotaAllowance.ValueInt32()),
+	group, err := createIdempotently(ctx, "group", func() (codersdk.Group, error) {
+		return client.CreateGroup(ctx, orgID, codersdk.CreateGroupRequest{
+			Name:           data.Name.ValueString(),
+			DisplayName:    data.DisplayName.ValueString(),
+			AvatarURL:      data.AvatarURL.ValueString(),
+			QuotaAllowance: int(data.QuotaAllowance.ValueInt32()),
+		})
+	}, func(_ time.Time, createErr error) (codersdk.Group, bool, error) {
+		// Groups don't record when they were created, so a group is only
+		// adopted if the create failed without a response, or the proxy in
+		// front of the deployment failed. A conflict means the group already
+		// existed, or was created by someone else in the meantime.
+		if isConflict(createErr) {
+			return codersdk.Group{}, false, nil
+		}
+		group, err := client.GroupByOrgAndName(ctx, orgID, data.Name.ValueString())
+		if isNotFound(err) {
+			return group, false, nil
+		}
+		return group, err == nil && group.Source == codersdk.GroupSourceUser, err
 	})
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create group, got error: %s", err))
@@ -182,28 +456,77 @@ func (r *GroupResource) Create(ctx context.Context, req resource.CreateRequest,
 	})
 	data.ID = UUIDValue(group.ID)
 	data.DisplayName = types.StringValue(group.DisplayName)
+	data.Source = types.StringValue(string(group.Source))
 
 	tflog.Info(ctx, "setting group members")
-	var members []string
-	resp.Diagnostics.Append(
-		data.Members.ElementsAs(ctx, &members, false)...,
-	)
+	members, diags := data.managedMembers(ctx)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
-		AddUsers: members,
-	})
+	addUsers := make([]string, 0, len(members))
+	for _, member := range members {
+		addUsers = append(addUsers, member.ValueString())
+	}
+	err = r.patchMembers(ctx, group.ID, addUsers, nil)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to add members to group, got error: %s", err))
+		if !rollbackCreate(ctx, resp, "group", func(ctx context.Context) error {
+			return client.DeleteGroup(ctx, group.ID)
+		}) {
+			savePartialCreate(ctx, resp, "group", &data)
+		}
 		return
 	}
 	tflog.Info(ctx, "successfully set group members")
 
+	resp.Diagnostics.Append(r.refreshTotalQuotaAllowance(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// adoptGroup manages an existing OIDC group, updating it to match the plan.
+func (r *GroupResource) adoptGroup(ctx context.Context, data *GroupResourceModel, group codersdk.Group, resp *resource.CreateResponse) {
+	tflog.Info(ctx, "adopting OIDC group", map[string]any{
+		"id": group.ID.String(),
+	})
+	add, remove, diags := data.memberChanges(ctx, group, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	quotaAllowance := int(data.QuotaAllowance.ValueInt32())
+	group, err := r.data.Client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+		DisplayName:    data.DisplayName.ValueStringPointer(),
+		AvatarURL:      data.AvatarURL.ValueStringPointer(),
+		QuotaAllowance: &quotaAllowance,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update adopted group, got error: %s", err))
+		return
+	}
+	err = r.patchMembers(ctx, group.ID, add, remove)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update members of adopted group, got error: %s", err))
+		return
+	}
+	tflog.Info(ctx, "successfully adopted group")
+	data.ID = UUIDValue(group.ID)
+	data.DisplayName = types.StringValue(group.DisplayName)
+	data.Source = types.StringValue(string(group.Source))
+	resp.Diagnostics.Append(r.refreshTotalQuotaAllowance(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+}
+
 func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data GroupResourceModel
 
@@ -219,6 +542,13 @@ func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	groupID := data.ID.ValueUUID()
 
 	group, err := client.Group(ctx, groupID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "group not found, removing from state", map[string]any{
+			"id": groupID.String(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get group, got error: %s", err))
 		return
@@ -228,13 +558,69 @@ func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	data.DisplayName = types.StringValue(group.DisplayName)
 	data.AvatarURL = types.StringValue(group.AvatarURL)
 	data.QuotaAllowance = types.Int32Value(int32(group.QuotaAllowance))
+	data.TotalQuotaAllowance = totalQuotaAllowance(group)
 	data.OrganizationID = UUIDValue(group.OrganizationID)
-	if !data.Members.IsNull() {
-		members := make([]attr.Value, 0, len(group.Members))
+	data.Source = types.StringValue(string(group.Source))
+	// Not set when importing
+	if data.AdoptOIDC.IsNull() {
+		data.AdoptOIDC = types.BoolValue(false)
+	}
+	// Not set when importing
+	if data.MembershipMode.IsNull() {
+		data.MembershipMode = types.StringValue(groupMembershipExact)
+	}
+	if data.managesMembers() {
+		curMembers := make([]uuid.UUID, 0, len(group.Members))
 		for _, member := range group.Members {
-			members = append(members, UUIDValue(member.ID))
+			curMembers = append(curMembers, member.ID)
+		}
+		if data.MembershipMode.ValueString() == groupMembershipAdditive {
+			// Only detect drift in the members we manage
+			managed, diags := data.managedMembers(ctx)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			curMembers = intersectMembers(curMembers, managed)
+		}
+		var explicitMembers, sourceMembers []UUID
+		if !data.Members.IsNull() {
+			resp.Diagnostics.Append(data.Members.ElementsAs(ctx, &explicitMembers, false)...)
+		}
+		if !data.SourceMembers.IsNull() {
+			resp.Diagnostics.Append(data.SourceMembers.ElementsAs(ctx, &sourceMembers, false)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		explicitSet := make(map[uuid.UUID]struct{}, len(explicitMembers))
+		for _, id := range explicitMembers {
+			explicitSet[id.ValueUUID()] = struct{}{}
+		}
+		sourceSet := make(map[uuid.UUID]struct{}, len(sourceMembers))
+		for _, id := range sourceMembers {
+			sourceSet[id.ValueUUID()] = struct{}{}
+		}
+		// Members only in the group because of a source group aren't drift
+		// in members.
+		members := make([]attr.Value, 0, len(curMembers))
+		fromSources := make([]attr.Value, 0, len(curMembers))
+		for _, id := range curMembers {
+			_, explicit := explicitSet[id]
+			_, fromSource := sourceSet[id]
+			if explicit || !fromSource {
+				members = append(members, UUIDValue(id))
+			}
+			if fromSource {
+				fromSources = append(fromSources, UUIDValue(id))
+			}
+		}
+		if !data.Members.IsNull() {
+			data.Members = types.SetValueMust(UUIDType, members)
+		}
+		if !data.SourceMembers.IsNull() {
+			data.SourceMembers = types.SetValueMust(UUIDType, fromSources)
 		}
-		data.Members = types.SetValueMust(UUIDType, members)
 	}
 
 	// Save updated data into Terraform state
@@ -251,32 +637,39 @@ func (r *GroupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	var prior GroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client := r.data.Client
 	if data.OrganizationID.IsUnknown() {
 		data.OrganizationID = UUIDValue(r.data.DefaultOrganizationID)
 	}
+	if data.DisplayName.IsUnknown() {
+		data.DisplayName = data.Name
+	}
 	groupID := data.ID.ValueUUID()
 
+	if data.SourceMembers.IsUnknown() {
+		var diags diag.Diagnostics
+		data.SourceMembers, diags = r.sourceGroupMembers(ctx, data.MemberSourceGroupIDs)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	group, err := client.Group(ctx, groupID)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get group, got error: %s", err))
 		return
 	}
-	var add []string
-	var remove []string
-	if !data.Members.IsNull() {
-		var plannedMembers []UUID
-		resp.Diagnostics.Append(
-			data.Members.ElementsAs(ctx, &plannedMembers, false)...,
-		)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		curMembers := make([]uuid.UUID, 0, len(group.Members))
-		for _, member := range group.Members {
-			curMembers = append(curMembers, member.ID)
-		}
-		add, remove = memberDiff(curMembers, plannedMembers)
+	add, remove, diags := data.memberChanges(ctx, group, &prior)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 	tflog.Info(ctx, "updating group", map[string]any{
 		"id":              groupID,
@@ -288,10 +681,9 @@ func (r *GroupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		"new_quota":       data.QuotaAllowance,
 	})
 
+	defer r.data.forgetGroups()
 	quotaAllowance := int(data.QuotaAllowance.ValueInt32())
 	_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
-		AddUsers:       add,
-		RemoveUsers:    remove,
 		Name:           data.Name.ValueString(),
 		DisplayName:    data.DisplayName.ValueStringPointer(),
 		AvatarURL:      data.AvatarURL.ValueStringPointer(),
@@ -301,12 +693,46 @@ func (r *GroupResource) Update(ctx context.Context, req resource.UpdateRequest,
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update group, got error: %s", err))
 		return
 	}
+	err = r.patchMembers(ctx, group.ID, add, remove)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update group members, got error: %s", err))
+		// Keep the prior members, so the remaining changes are planned again
+		// once the members that were changed are refreshed.
+		data.Members = prior.Members
+		data.SourceMembers = prior.SourceMembers
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
 	tflog.Info(ctx, "successfully updated group")
 
+	resp.Diagnostics.Append(r.refreshTotalQuotaAllowance(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// refreshTotalQuotaAllowance sets the total quota allowance from the current
+// members of the group, as the group may have members Terraform doesn't
+// manage.
+func (r *GroupResource) refreshTotalQuotaAllowance(ctx context.Context, data *GroupResourceModel) (diags diag.Diagnostics) {
+	group, err := r.data.Client.Group(ctx, data.ID.ValueUUID())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to get group, got error: %s", err))
+		return diags
+	}
+	data.TotalQuotaAllowance = totalQuotaAllowance(group)
+	return diags
+}
+
+// totalQuotaAllowance returns the number of quota credits the group adds to
+// the budgets of its members.
+func totalQuotaAllowance(group codersdk.Group) types.Int64 {
+	return types.Int64Value(int64(group.QuotaAllowance) * int64(len(group.Members)))
+}
+
 func (r *GroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data GroupResourceModel
 
@@ -323,6 +749,7 @@ func (r *GroupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	tflog.Info(ctx, "deleting group", map[string]any{
 		"id": groupID,
 	})
+	defer r.data.forgetGroups()
 	err := client.DeleteGroup(ctx, groupID)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete group, got error: %s", err))
@@ -343,7 +770,7 @@ func (r *GroupResource) ImportState(ctx context.Context, req resource.ImportStat
 			return
 		}
 	} else if len(idParts) == 2 {
-		org, err := client.OrganizationByName(ctx, idParts[0])
+		org, err := r.data.organizationByName(ctx, idParts[0])
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get organization with name %s: %s", idParts[0], err))
 			return
@@ -360,12 +787,163 @@ func (r *GroupResource) ImportState(ctx context.Context, req resource.ImportStat
 	}
 	group, err := client.Group(ctx, groupID)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get imported group, got error: %s", err))
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get group, got error: %s", err))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), groupID.String())...)
+	// Import the members as managed, so the plan after importing is clean.
+	// Members of OIDC groups are synced by Coder, so are left unmanaged.
+	if group.Source != codersdk.GroupSourceOIDC {
+		members := make([]attr.Value, 0, len(group.Members))
+		for _, member := range group.Members {
+			members = append(members, UUIDValue(member.ID))
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("members"), types.SetValueMust(UUIDType, members))...)
+	}
+	// The remaining attributes are populated by Read.
+}
+
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *GroupResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(ctx, r)
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan.
+func (r *GroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when destroying.
+	if req.Plan.Raw.IsNull() {
 		return
 	}
-	if group.Source == "oidc" {
-		resp.Diagnostics.AddError("Client Error", "Cannot import groups created via OIDC")
+	if r.data != nil {
+		planOrganizationID(ctx, r.data, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	var plan GroupResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), groupID.String())...)
+	// Plan the current members of the source groups, so changes to them are
+	// applied.
+	if r.data != nil && !plan.MemberSourceGroupIDs.IsUnknown() {
+		sourceMembers, diags := r.sourceGroupMembers(ctx, plan.MemberSourceGroupIDs)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_members"), sourceMembers)...)
+	}
+
+	if r.data != nil && r.data.ValidateMembers && !plan.Members.IsUnknown() && !plan.Members.IsNull() {
+		var members []UUID
+		resp.Diagnostics.Append(plan.Members.ElementsAs(ctx, &members, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			if !member.IsUnknown() {
+				ids = append(ids, member.ValueString())
+			}
+		}
+		resp.Diagnostics.Append(checkUsersExist(ctx, r.data.Client, path.Root("members"), ids)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Without groups, creating the group fails with a clearer error.
+	if r.data != nil && r.data.Features[codersdk.FeatureTemplateRBAC].Enabled && !plan.OrganizationID.IsUnknown() {
+		resp.Diagnostics.Append(r.checkNameConflict(ctx, req, plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Nothing else to check when creating.
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var state GroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// OIDC groups are imported without checking the configuration, so they
+	// can only be managed once adopted.
+	if state.Source.ValueString() == string(codersdk.GroupSourceOIDC) && !plan.AdoptOIDC.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("adopt_oidc"), "Cannot Manage OIDC Group",
+			fmt.Sprintf("Group %q was created by OIDC group sync. Set adopt_oidc = true to manage it with Terraform.", state.Name.ValueString()))
+	}
+
+	// Keep the total quota allowance unless the allowance or members change,
+	// rather than planning it as unknown whenever the group changes.
+	var sourceMembers types.Set
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("source_members"), &sourceMembers)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.QuotaAllowance.Equal(state.QuotaAllowance) && plan.Members.Equal(state.Members) && sourceMembers.Equal(state.SourceMembers) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("total_quota_allowance"), state.TotalQuotaAllowance)...)
+	}
+}
+
+// checkNameConflict returns an error if the group is being created or renamed,
+// and another group in the organization already has the name.
+func (r *GroupResource) checkNameConflict(ctx context.Context, req resource.ModifyPlanRequest, plan GroupResourceModel) diag.Diagnostics {
+	name, changed, diags := plannedChange[types.String](ctx, req, path.Root("name"))
+	if !changed || diags.HasError() {
+		return diags
+	}
+	existing, err := r.data.Client.GroupByOrgAndName(ctx, plan.OrganizationID.ValueUUID(), name)
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		diags.AddError("Client Error", fmt.Sprintf("Unable to check for group %q, got error: %s", name, err))
+	case req.State.Raw.IsNull() && plan.AdoptOIDC.ValueBool() && existing.Source == codersdk.GroupSourceOIDC:
+		// The existing group is adopted when creating.
+	default:
+		diags.AddAttributeError(path.Root("name"), "Group Name Conflict",
+			fmt.Sprintf("A group named %q already exists in the organization. Choose another name, or import the existing group with `terraform import`.", name))
+	}
+	return diags
+}
+
+// sourceGroupMembers returns the members of the given groups, or unknown if
+// any of the group IDs are unknown.
+func (r *GroupResource) sourceGroupMembers(ctx context.Context, groupIDs types.Set) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if groupIDs.IsNull() {
+		return types.SetNull(UUIDType), diags
+	}
+	var ids []UUID
+	diags.Append(groupIDs.ElementsAs(ctx, &ids, false)...)
+	if diags.HasError() {
+		return types.SetNull(UUIDType), diags
+	}
+	for _, id := range ids {
+		if id.IsUnknown() {
+			return types.SetUnknown(UUIDType), diags
+		}
+	}
+
+	seen := map[uuid.UUID]struct{}{}
+	members := []attr.Value{}
+	for _, id := range ids {
+		group, err := r.data.Client.Group(ctx, id.ValueUUID())
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get source group %s, got error: %s", id.ValueString(), err))
+			return types.SetNull(UUIDType), diags
+		}
+		for _, member := range group.Members {
+			if _, ok := seen[member.ID]; ok {
+				continue
+			}
+			seen[member.ID] = struct{}{}
+			members = append(members, UUIDValue(member.ID))
+		}
+	}
+	return types.SetValueMust(UUIDType, members), diags
 }
@@ -2,8 +2,11 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"text/template"
@@ -11,6 +14,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/terraform-provider-coderd/integration"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,14 +55,43 @@ func TestAccGroupResource(t *testing.T) {
 		Members:        PtrTo([]string{user1.ID.String()}),
 	}
 
+	avatarFile := filepath.Join(t.TempDir(), "avatar.svg")
+	err = os.WriteFile(avatarFile, []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`), 0o600)
+	require.NoError(t, err)
+
 	cfg2 := cfg1
 	cfg2.Name = PtrTo("example-group-new")
+	cfg2.AvatarUrl = nil
+	cfg2.AvatarFile = &avatarFile
 	cfg2.DisplayName = PtrTo("Example Group New")
 	cfg2.Members = PtrTo([]string{user2.ID.String()})
 
 	cfg3 := cfg2
 	cfg3.Members = nil
 
+	checkServerMembers := func(name string, want ...codersdk.User) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			group, err := client.GroupByOrgAndName(ctx, firstUser.OrganizationIDs[0], name)
+			if err != nil {
+				return err
+			}
+			got := make([]string, 0, len(group.Members))
+			for _, member := range group.Members {
+				got = append(got, member.ID.String())
+			}
+			expected := make([]string, 0, len(want))
+			for _, user := range want {
+				expected = append(expected, user.ID.String())
+			}
+			slices.Sort(got)
+			slices.Sort(expected)
+			if !slices.Equal(expected, got) {
+				return fmt.Errorf("unexpected group members %v", got)
+			}
+			return nil
+		}
+	}
+
 	t.Run("CreateImportUpdateReadOk", func(t *testing.T) {
 		resource.Test(t, resource.TestCase{
 			IsUnitTest:               true,
@@ -73,25 +106,26 @@ func TestAccGroupResource(t *testing.T) {
 						resource.TestCheckResourceAttr("coderd_group.test", "display_name", "Example Group"),
 						resource.TestCheckResourceAttr("coderd_group.test", "avatar_url", "https://google.com"),
 						resource.TestCheckResourceAttr("coderd_group.test", "quota_allowance", "100"),
+						resource.TestCheckResourceAttr("coderd_group.test", "total_quota_allowance", "100"),
 						resource.TestCheckResourceAttr("coderd_group.test", "organization_id", firstUser.OrganizationIDs[0].String()),
 						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
 						resource.TestCheckResourceAttr("coderd_group.test", "members.0", user1.ID.String()),
+						resource.TestCheckResourceAttr("coderd_group.test", "source", "user"),
+						resource.TestCheckResourceAttr("coderd_group.test", "adopt_oidc", "false"),
 					),
 				},
 				// Import by ID
 				{
-					ResourceName:            "coderd_group.test",
-					ImportState:             true,
-					ImportStateVerify:       true,
-					ImportStateVerifyIgnore: []string{"members"},
+					ResourceName:      "coderd_group.test",
+					ImportState:       true,
+					ImportStateVerify: true,
 				},
 				// Import by org name and group name
 				{
-					ResourceName:            "coderd_group.test",
-					ImportState:             true,
-					ImportStateId:           "default/example-group",
-					ImportStateVerify:       true,
-					ImportStateVerifyIgnore: []string{"members"},
+					ResourceName:      "coderd_group.test",
+					ImportState:       true,
+					ImportStateId:     "default/example-group",
+					ImportStateVerify: true,
 				},
 				// Update and Read
 				{
@@ -99,6 +133,7 @@ func TestAccGroupResource(t *testing.T) {
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttr("coderd_group.test", "name", "example-group-new"),
 						resource.TestCheckResourceAttr("coderd_group.test", "display_name", "Example Group New"),
+						resource.TestMatchResourceAttr("coderd_group.test", "avatar_url", regexp.MustCompile(`^data:image/svg\+xml;base64,`)),
 						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
 						resource.TestCheckResourceAttr("coderd_group.test", "members.0", user2.ID.String()),
 					),
@@ -114,6 +149,208 @@ func TestAccGroupResource(t *testing.T) {
 		})
 	})
 
+	t.Run("AdditiveMembers", func(t *testing.T) {
+		cfgAdditive := testAccGroupResourceconfig{
+			URL:            client.URL.String(),
+			Token:          client.SessionToken(),
+			Name:           PtrTo("additive-group"),
+			Members:        PtrTo([]string{user1.ID.String()}),
+			MembershipMode: PtrTo("additive"),
+		}
+		cfgAdditive2 := cfgAdditive
+		cfgAdditive2.Members = PtrTo([]string{firstUser.ID.String()})
+
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfgAdditive.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "membership_mode", "additive"),
+						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
+						checkServerMembers("additive-group", user1),
+					),
+				},
+				// Members added outside of Terraform are left alone
+				{
+					PreConfig: func() {
+						group, err := client.GroupByOrgAndName(ctx, firstUser.OrganizationIDs[0], "additive-group")
+						require.NoError(t, err)
+						_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+							AddUsers: []string{user2.ID.String()},
+						})
+						require.NoError(t, err)
+					},
+					Config: cfgAdditive.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "members.0", user1.ID.String()),
+						checkServerMembers("additive-group", user1, user2),
+					),
+				},
+				// Members removed from the configuration are removed
+				{
+					Config: cfgAdditive2.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "members.0", firstUser.ID.String()),
+						checkServerMembers("additive-group", firstUser, user2),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("MemberSourceGroups", func(t *testing.T) {
+		sourceGroup, err := client.CreateGroup(ctx, firstUser.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "source-group",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, sourceGroup.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String()},
+		})
+		require.NoError(t, err)
+
+		cfgSource := testAccGroupResourceconfig{
+			URL:                  client.URL.String(),
+			Token:                client.SessionToken(),
+			Name:                 PtrTo("nested-group"),
+			Members:              PtrTo([]string{user1.ID.String()}),
+			MemberSourceGroupIDs: PtrTo([]string{sourceGroup.ID.String()}),
+		}
+
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfgSource.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "source_members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "source_members.0", user2.ID.String()),
+						checkServerMembers("nested-group", user1, user2),
+					),
+				},
+				// Changes to the source group are applied
+				{
+					PreConfig: func() {
+						_, err := client.PatchGroup(ctx, sourceGroup.ID, codersdk.PatchGroupRequest{
+							AddUsers:    []string{firstUser.ID.String()},
+							RemoveUsers: []string{user2.ID.String()},
+						})
+						require.NoError(t, err)
+					},
+					Config: cfgSource.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "source_members.#", "1"),
+						resource.TestCheckResourceAttr("coderd_group.test", "source_members.0", firstUser.ID.String()),
+						checkServerMembers("nested-group", user1, firstUser),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("DefaultOrganization", func(t *testing.T) {
+		org, err := client.CreateOrganization(ctx, codersdk.CreateOrganizationRequest{
+			Name: "other-org",
+		})
+		require.NoError(t, err)
+		defaultOrgID := firstUser.OrganizationIDs[0].String()
+		cfgOther := testAccGroupResourceconfig{
+			URL:            client.URL.String(),
+			Token:          client.SessionToken(),
+			Name:           PtrTo("org-group"),
+			OrganizationID: PtrTo(org.ID.String()),
+		}
+		cfgDefault := cfgOther
+		cfgDefault.OrganizationID = nil
+		cfgConfiguredDefault := cfgDefault
+		cfgConfiguredDefault.DefaultOrganizationID = PtrTo(defaultOrgID)
+		cfgExplicitDefault := cfgOther
+		cfgExplicitDefault.OrganizationID = PtrTo(defaultOrgID)
+
+		var groupID string
+		recordGroup := resource.TestCheckResourceAttrWith("coderd_group.test", "id", func(value string) error {
+			groupID = value
+			return nil
+		})
+		sameGroup := resource.TestCheckResourceAttrWith("coderd_group.test", "id", func(value string) error {
+			if value != groupID {
+				return fmt.Errorf("expected group %s to be kept, got %s", groupID, value)
+			}
+			return nil
+		})
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfgOther.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "organization_id", org.ID.String()),
+						recordGroup,
+					),
+				},
+				// Unsetting the organization keeps the group when the
+				// provider's default organization isn't configured
+				{
+					Config: cfgDefault.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "organization_id", org.ID.String()),
+						sameGroup,
+					),
+				},
+				// Configuring the default organization moves the group to it
+				{
+					Config: cfgConfiguredDefault.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_group.test", "organization_id", defaultOrgID),
+						recordGroup,
+					),
+				},
+				// Setting the default organization explicitly keeps the group
+				{
+					Config: cfgExplicitDefault.String(t),
+					Check:  sameGroup,
+				},
+				{
+					Config: cfgDefault.String(t),
+					Check:  sameGroup,
+				},
+			},
+		})
+	})
+
+	t.Run("NameConflict", func(t *testing.T) {
+		_, err := client.CreateGroup(ctx, firstUser.OrganizationIDs[0], codersdk.CreateGroupRequest{
+			Name: "taken-group",
+		})
+		require.NoError(t, err)
+		cfgTaken := testAccGroupResourceconfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			Name:  PtrTo("taken-group"),
+		}
+		resource.Test(t, resource.TestCase{
+			IsUnitTest:               true,
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config:      cfgTaken.String(t),
+					ExpectError: regexp.MustCompile("Group Name Conflict"),
+				},
+			},
+		})
+	})
+
 	t.Run("CreateUnmanagedMembersOk", func(t *testing.T) {
 		resource.Test(t, resource.TestCase{
 			IsUnitTest:               true,
@@ -165,32 +402,42 @@ func TestAccGroupResourceAGPL(t *testing.T) {
 }
 
 type testAccGroupResourceconfig struct {
-	URL   string
-	Token string
+	URL                   string
+	Token                 string
+	DefaultOrganizationID *string
 
 	Name           *string
 	DisplayName    *string
 	AvatarUrl      *string
+	AvatarFile     *string
 	QuotaAllowance *int32
 	OrganizationID *string
 	Members        *[]string
+	MembershipMode *string
+
+	MemberSourceGroupIDs *[]string
 }
 
 func (c testAccGroupResourceconfig) String(t *testing.T) string {
 	t.Helper()
 	tpl := `
 provider coderd {
-	url   = "{{.URL}}"
-	token = "{{.Token}}"
+	url                     = "{{.URL}}"
+	token                   = "{{.Token}}"
+	default_organization_id = {{orNull .DefaultOrganizationID}}
 }
 
 resource "coderd_group" "test" {
 	name              = {{orNull .Name}}
 	display_name      = {{orNull .DisplayName}}
 	avatar_url        = {{orNull .AvatarUrl}}
+	avatar_file       = {{orNull .AvatarFile}}
 	quota_allowance   = {{orNull .QuotaAllowance}}
 	organization_id   = {{orNull .OrganizationID}}
 	members           = {{orNull .Members}}
+	membership_mode   = {{orNull .MembershipMode}}
+
+	member_source_group_ids = {{orNull .MemberSourceGroupIDs}}
 }
 `
 	funcMap := template.FuncMap{
@@ -205,3 +452,20 @@ resource "coderd_group" "test" {
 	require.NoError(t, err)
 	return buf.String()
 }
+
+func TestMemberBatches(t *testing.T) {
+	t.Parallel()
+	add := []string{"a", "b", "c"}
+	remove := []string{"d", "e"}
+
+	require.Equal(t, []codersdk.PatchGroupRequest{
+		{AddUsers: []string{"a", "b"}},
+		{AddUsers: []string{"c"}, RemoveUsers: []string{"d"}},
+		{RemoveUsers: []string{"e"}},
+	}, memberBatches(add, remove, 2))
+	require.Equal(t, []codersdk.PatchGroupRequest{
+		{AddUsers: add, RemoveUsers: remove},
+	}, memberBatches(add, remove, 0))
+	require.Len(t, memberBatches(add, remove, 5), 1)
+	require.Empty(t, memberBatches(nil, nil, 2))
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// hashCacheVersion is changed when the directory hash changes, so hashes
+// cached by other versions of the provider aren't used.
+const hashCacheVersion = 1
+
+// hashCache stores template directory hashes on disk, keyed by the paths,
+// sizes and modification times of the files, so directories that haven't
+// changed aren't read again on every plan. If dir is empty, nothing is
+// cached.
+type hashCache struct {
+	dir string
+}
+
+type hashCacheEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	Hash        string `json:"hash"`
+}
+
+// directoryHash returns the hash computed by computeDirectoryHash, from the
+// cache if the files are unchanged.
+func (c hashCache) directoryHash(directory string, rules ignoreRules, normalizeLineEndings bool) (string, error) {
+	if c.dir == "" {
+		return computeDirectoryHash(directory, rules, normalizeLineEndings)
+	}
+	abs, err := filepath.Abs(directory)
+	if err != nil {
+		return "", err
+	}
+	// The fingerprint is taken before hashing, so files changed while
+	// hashing don't match it next time.
+	fingerprint, err := directoryFingerprint(directory, rules)
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(fmt.Sprintf("%d:%t:%s", hashCacheVersion, normalizeLineEndings, abs)))
+	path := filepath.Join(c.dir, hex.EncodeToString(key[:])+".json")
+
+	var entry hashCacheEntry
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &entry) == nil && entry.Fingerprint == fingerprint {
+		return entry.Hash, nil
+	}
+	hash, err := computeDirectoryHash(directory, rules, normalizeLineEndings)
+	if err != nil {
+		return "", err
+	}
+	// Failing to cache the hash only makes the next plan slower.
+	_ = writeHashCacheEntry(path, hashCacheEntry{Fingerprint: fingerprint, Hash: hash})
+	return hash, nil
+}
+
+// directoryFingerprint returns a hash of the paths, sizes and modification
+// times of the files in the directory that are not excluded by the rules.
+func directoryFingerprint(directory string, rules ignoreRules) (string, error) {
+	var entries []string
+	err := walkIncluded(directory, rules, func(_, rel string, info os.FileInfo) error {
+		if info.Mode().IsRegular() {
+			entries = append(entries, fmt.Sprintf("%d:%s:%d:%d", len(rel), rel, info.Size(), info.ModTime().UnixNano()))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(entries)
+	hash := sha256.New()
+	for _, entry := range entries {
+		_, _ = fmt.Fprintln(hash, entry)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeHashCacheEntry replaces the cache entry at path, so concurrent plans
+// never read a partially written entry.
+func writeHashCacheEntry(path string, entry hashCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCache(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.tf")
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	writeFile := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	writeFile("resource {}")
+	cache := hashCache{dir: t.TempDir()}
+
+	original, err := cache.directoryHash(dir, nil, false)
+	require.NoError(t, err)
+	expected, err := computeDirectoryHash(dir, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, original)
+
+	// A change that keeps the size and modification time isn't detected,
+	// which shows the files weren't read again.
+	writeFile("resource []")
+	cached, err := cache.directoryHash(dir, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, original, cached)
+
+	// The hash is cached separately when normalizing line endings.
+	normalized, err := cache.directoryHash(dir, nil, true)
+	require.NoError(t, err)
+	require.NotEqual(t, original, normalized)
+
+	modTime = modTime.Add(time.Minute)
+	writeFile("resource []")
+	changed, err := cache.directoryHash(dir, nil, false)
+	require.NoError(t, err)
+	require.NotEqual(t, original, changed)
+	expected, err = computeDirectoryHash(dir, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, changed)
+
+	// Without a cache directory, the hash is always computed.
+	uncached, err := hashCache{}.directoryHash(dir, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, uncached)
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maxIconFileSize is the largest image that can be embedded as an icon. Icons
+// are stored inline, and returned with every template or group, so they
+// should be kept small.
+const maxIconFileSize = 256 << 10
+
+// iconDataURL reads the image at the given path and returns it as a data URL,
+// which can be used anywhere an icon or avatar URL is accepted.
+func iconDataURL(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read icon file: %w", err)
+	}
+	if info.Size() > maxIconFileSize {
+		return "", fmt.Errorf("icon file %q is %d bytes, which exceeds the limit of %d bytes", path, info.Size(), maxIconFileSize)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read icon file: %w", err)
+	}
+	// Content sniffing doesn't detect SVGs, so prefer the file extension.
+	mediaType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(content)
+	}
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	if !strings.HasPrefix(mediaType, "image/") {
+		return "", fmt.Errorf("icon file %q must be an image, got %s", path, mediaType)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mediaType, base64.StdEncoding.EncodeToString(content)), nil
+}
+
+type iconFilePlanModifier struct {
+	fileAttr string
+}
+
+// NewIconFilePlanModifier plans an icon or avatar URL attribute that isn't
+// configured as the data URL of the image in its sibling file attribute, if
+// that is configured.
+func NewIconFilePlanModifier(fileAttr string) planmodifier.String {
+	return &iconFilePlanModifier{
+		fileAttr: fileAttr,
+	}
+}
+
+// Description implements planmodifier.String.
+func (m *iconFilePlanModifier) Description(ctx context.Context) string {
+	return m.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements planmodifier.String.
+func (m *iconFilePlanModifier) MarkdownDescription(context.Context) string {
+	return fmt.Sprintf("Embed the image in `%s` as a data URL when configured.", m.fileAttr)
+}
+
+// PlanModifyString implements planmodifier.String.
+func (m *iconFilePlanModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	var file types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, req.Path.ParentPath().AtName(m.fileAttr), &file)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch {
+	case file.IsUnknown():
+		resp.PlanValue = types.StringUnknown()
+	case file.IsNull():
+		return
+	default:
+		dataURL, err := iconDataURL(file.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(req.Path.ParentPath().AtName(m.fileAttr), "Invalid Icon File", err.Error())
+			return
+		}
+		resp.PlanValue = types.StringValue(dataURL)
+	}
+}
+
+var _ planmodifier.String = &iconFilePlanModifier{}
+
+// emojiShortcodeRegex matches emoji shortcodes, e.g. `:rocket:`.
+var emojiShortcodeRegex = regexp.MustCompile(`^:[a-z0-9_+-]+:$`)
+
+// iconPathPrefixes are the relative paths the dashboard serves emojis and
+// icons from.
+var iconPathPrefixes = []string{"/emojis/", "/icon/"}
+
+// validateIconURL returns an error if the value isn't an icon or avatar the
+// dashboard can render: an https URL, a data URL of an image, a path to one of
+// the dashboard's emojis or icons, or an emoji shortcode. An empty value
+// removes the icon.
+func validateIconURL(value string) error {
+	switch {
+	case value == "":
+		return nil
+	case strings.HasPrefix(value, "data:"):
+		if !strings.HasPrefix(value, "data:image/") {
+			return fmt.Errorf("data URL %q must be an image", truncate(value, 32))
+		}
+		return nil
+	case strings.HasPrefix(value, "/"):
+		if path.Clean(value) != value {
+			return fmt.Errorf("path %q must be clean", value)
+		}
+		for _, prefix := range iconPathPrefixes {
+			if strings.HasPrefix(value, prefix) && len(value) > len(prefix) {
+				return nil
+			}
+		}
+		return fmt.Errorf("path %q must be in one of %s", value, strings.Join(iconPathPrefixes, ", "))
+	case emojiShortcodeRegex.MatchString(value):
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL: %w", value, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%q must be an https URL, a path in one of %s, or an emoji shortcode", value, strings.Join(iconPathPrefixes, ", "))
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+type iconURLValidator struct{}
+
+// NewIconURLValidator validates that a string is an icon or avatar that the
+// dashboard can render.
+func NewIconURLValidator() validator.String {
+	return &iconURLValidator{}
+}
+
+// Description implements validator.String.
+func (v *iconURLValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements validator.String.
+func (v *iconURLValidator) MarkdownDescription(context.Context) string {
+	return "Validate that the value is an https URL, an image data URL, a path to a built-in emoji or icon, or an emoji shortcode."
+}
+
+// ValidateString implements validator.String.
+func (v *iconURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := validateIconURL(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Icon", err.Error())
+	}
+}
+
+var _ validator.String = &iconURLValidator{}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIconDataURL(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+		return path
+	}
+
+	// Detected by extension
+	dataURL, err := iconDataURL(write("icon.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)))
+	require.NoError(t, err)
+	require.Equal(t, "data:image/svg+xml;base64,PHN2ZyB4bWxucz0iaHR0cDovL3d3dy53My5vcmcvMjAwMC9zdmciLz4=", dataURL)
+
+	// Detected by content
+	png := []byte("\x89PNG\r\n\x1a\n")
+	dataURL, err = iconDataURL(write("icon", png))
+	require.NoError(t, err)
+	require.Equal(t, "data:image/png;base64,iVBORw0KGgo=", dataURL)
+
+	_, err = iconDataURL(write("icon.txt", []byte("not an image")))
+	require.ErrorContains(t, err, "must be an image")
+
+	_, err = iconDataURL(write("large.png", make([]byte, maxIconFileSize+1)))
+	require.ErrorContains(t, err, "exceeds the limit")
+
+	_, err = iconDataURL(filepath.Join(dir, "missing.png"))
+	require.ErrorContains(t, err, "failed to read icon file")
+}
+
+func TestValidateIconURL(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{name: "Empty", value: ""},
+		{name: "HTTPS", value: "https://example.com/icon.png"},
+		{name: "DataURL", value: "data:image/png;base64,iVBORw0KGgo="},
+		{name: "Emoji", value: "/emojis/1f680.png"},
+		{name: "Icon", value: "/icon/code.svg"},
+		{name: "Shortcode", value: ":rocket:"},
+		{name: "HTTP", value: "http://example.com/icon.png", err: "must be an https URL"},
+		{name: "NoHost", value: "https:///icon.png", err: "must be an https URL"},
+		{name: "Bare", value: "icon.png", err: "must be an https URL"},
+		{name: "OtherPath", value: "/path/to/icon.png", err: "must be in one of"},
+		{name: "PrefixOnly", value: "/emojis", err: "must be in one of"},
+		{name: "Traversal", value: "/emojis/../api/v2", err: "must be clean"},
+		{name: "NonImageDataURL", value: "data:text/html,<script></script>", err: "must be an image"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateIconURL(c.value)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// createAttempts is the number of times an object is created before
+	// giving up, if creating it fails transiently.
+	createAttempts = 3
+	// createRetryDelay is the delay before retrying a create, which doubles
+	// on each retry.
+	createRetryDelay = time.Second
+	// createClockSkew is how far the deployment's clock may be behind ours
+	// when comparing the time an object was created with when we started
+	// creating it.
+	createClockSkew = time.Minute
+)
+
+// createIdempotently calls create, retrying if it fails transiently. A create
+// that fails may still have created the object, such as when the response is
+// lost to a network error, or a retried request conflicts with the object
+// the first request created. Before failing or retrying, find is called to
+// look up the object by name. If find reports the object was created by us,
+// given the time we started creating it and the error the create failed with,
+// it's adopted instead of failing.
+//
+// find returns false if the object doesn't exist.
+func createIdempotently[T any](ctx context.Context, kind string, create func() (T, error), find func(start time.Time, err error) (T, bool, error)) (T, error) {
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		obj, err := create()
+		if err == nil || !mayHaveCreated(ctx, err) {
+			return obj, err
+		}
+		found, ours, findErr := find(start, err)
+		if findErr != nil {
+			return obj, errors.Join(err, fmt.Errorf("failed to check whether the %s was created: %w", kind, findErr))
+		}
+		if ours {
+			tflog.Warn(ctx, fmt.Sprintf("creating %s failed, but it was created, so adopting it", kind), map[string]any{
+				"error": err.Error(),
+			})
+			return found, nil
+		}
+		if isConflict(err) || attempt+1 >= createAttempts {
+			return obj, err
+		}
+		delay := createRetryDelay << attempt
+		tflog.Warn(ctx, fmt.Sprintf("creating %s failed, retrying", kind), map[string]any{
+			"error":   err.Error(),
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return obj, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// createdSince returns whether an object created at createdAt could have been
+// created by a request started at start.
+func createdSince(createdAt, start time.Time) bool {
+	return !createdAt.Before(start.Add(-createClockSkew))
+}
+
+// mayHaveCreated returns whether a create that failed with the error may
+// have created the object anyway.
+func mayHaveCreated(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var sdkErr *codersdk.Error
+	if !errors.As(err, &sdkErr) {
+		// The request failed without a response, so may have been
+		// processed.
+		return true
+	}
+	switch sdkErr.StatusCode() {
+	case http.StatusConflict, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIdempotently(t *testing.T) {
+	t.Parallel()
+	statusErr := func(status int) error {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"message":"error"}`))
+		}))
+		defer srv.Close()
+		srvURL, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		_, err = codersdk.New(srvURL).User(context.Background(), "test")
+		require.Error(t, err)
+		return err
+	}
+	networkErr := errors.New("connection reset by peer")
+
+	cases := []struct {
+		name    string
+		errs    []error
+		found   bool
+		ours    bool
+		creates int
+		finds   int
+		result  string
+		err     string
+	}{
+		{
+			name:    "Created",
+			creates: 1,
+			result:  "created",
+		},
+		{
+			name:    "LostResponse",
+			errs:    []error{networkErr},
+			found:   true,
+			ours:    true,
+			creates: 1,
+			finds:   1,
+			result:  "found",
+		},
+		{
+			name:    "RetriedConflict",
+			errs:    []error{statusErr(http.StatusConflict)},
+			found:   true,
+			ours:    true,
+			creates: 1,
+			finds:   1,
+			result:  "found",
+		},
+		{
+			name:    "ExistingConflict",
+			errs:    []error{statusErr(http.StatusConflict)},
+			found:   true,
+			creates: 1,
+			finds:   1,
+			err:     "error",
+		},
+		{
+			name:    "Retried",
+			errs:    []error{statusErr(http.StatusServiceUnavailable)},
+			creates: 2,
+			finds:   1,
+			result:  "created",
+		},
+		{
+			name:    "BadRequest",
+			errs:    []error{statusErr(http.StatusBadRequest)},
+			creates: 1,
+			err:     "error",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			var creates, finds int
+			result, err := createIdempotently(context.Background(), "test", func() (string, error) {
+				creates++
+				if creates <= len(c.errs) {
+					return "", c.errs[creates-1]
+				}
+				return "created", nil
+			}, func(time.Time, error) (string, bool, error) {
+				finds++
+				if !c.found {
+					return "", false, nil
+				}
+				return "found", c.ours, nil
+			})
+			require.Equal(t, c.creates, creates)
+			require.Equal(t, c.finds, finds)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.result, result)
+		})
+	}
+}
+
+func TestCreatedSince(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	require.True(t, createdSince(start.Add(time.Second), start))
+	require.True(t, createdSince(start.Add(-time.Second), start))
+	require.False(t, createdSince(start.Add(-time.Hour), start))
+}
@@ -0,0 +1,221 @@
+package provider
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cdr.dev/slog"
+)
+
+// terraformIgnoreFile is read from the root of a template version's directory,
+// and lists patterns of files to exclude from the template version.
+const terraformIgnoreFile = ".terraformignore"
+
+// defaultIgnorePatterns are excluded from every template version, and can be
+// re-included with a negated pattern.
+var defaultIgnorePatterns = []string{
+	".git/",
+	".terraform/",
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreRules is an ordered list of gitignore-style patterns. The last rule
+// matching a path determines whether it is excluded.
+type ignoreRules []ignoreRule
+
+// loadIgnoreRules returns the default rules, followed by the rules in the
+// directory's .terraformignore file, if any, followed by the given patterns.
+func loadIgnoreRules(directory string, exclude []string) (ignoreRules, error) {
+	patterns := append([]string{}, defaultIgnorePatterns...)
+	f, err := os.Open(filepath.Join(directory, terraformIgnoreFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open %s: %w", terraformIgnoreFile, err)
+	}
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			patterns = append(patterns, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", terraformIgnoreFile, err)
+		}
+	}
+	patterns = append(patterns, exclude...)
+	return parseIgnorePatterns(patterns)
+}
+
+func parseIgnorePatterns(patterns []string) (ignoreRules, error) {
+	rules := make(ignoreRules, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimSuffix(pattern, "/")
+		}
+		if strings.HasPrefix(pattern, "/") {
+			rule.anchored = true
+			pattern = strings.TrimPrefix(pattern, "/")
+		}
+		// A pattern with a slash in the middle is relative to the root.
+		if strings.Contains(strings.TrimPrefix(pattern, "**/"), "/") {
+			rule.anchored = true
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		rule.pattern = pattern
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// excluded returns true if the path, relative to the root of the directory
+// and slash-separated, should be excluded.
+func (r ignoreRules) excluded(rel string, isDir bool) bool {
+	excluded := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(rel) {
+			excluded = !rule.negate
+		}
+	}
+	return excluded
+}
+
+func (r ignoreRule) matches(rel string) bool {
+	if !r.anchored {
+		ok, _ := path.Match(r.pattern, path.Base(rel))
+		return ok
+	}
+	pattern := r.pattern
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = strings.TrimPrefix(pattern, "**/")
+		// Try the pattern against the path and every subpath.
+		for {
+			if ok, _ := path.Match(pattern, rel); ok {
+				return true
+			}
+			i := strings.Index(rel, "/")
+			if i < 0 {
+				return false
+			}
+			rel = rel[i+1:]
+		}
+	}
+	ok, _ := path.Match(pattern, rel)
+	return ok
+}
+
+// walkIncluded calls fn for every file and directory within the directory
+// that is not excluded by the rules, in lexical order. Excluded directories
+// are not descended into.
+func walkIncluded(directory string, rules ignoreRules, fn func(path, rel string, info os.FileInfo) error) error {
+	return filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if rules.excluded(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(path, rel, info)
+	})
+}
+
+// tarDirectory writes a tar archive of the files in the directory that are
+// not excluded by the rules, failing if the archive would exceed limit bytes.
+// directorySize returns the number and total size of the regular files in the
+// directory that are not excluded by the rules.
+func directorySize(directory string, rules ignoreRules) (files int, size int64, err error) {
+	err = walkIncluded(directory, rules, func(_, _ string, info os.FileInfo) error {
+		if info.Mode().IsRegular() {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	return files, size, err
+}
+
+func tarDirectory(ctx context.Context, w io.Writer, logger slog.Logger, directory string, rules ignoreRules, limit int64) error {
+	tw := tar.NewWriter(w)
+	var written int64
+	err := walkIncluded(directory, rules, func(path, rel string, info os.FileInfo) error {
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			var err error
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		} else if !info.IsDir() && !info.Mode().IsRegular() {
+			// Skip sockets, devices and other special files.
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		logger.Debug(ctx, "adding file to template archive", slog.F("path", rel))
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		written += info.Size()
+		if written > limit {
+			return fmt.Errorf("archive too big: exceeds the limit of %d bytes", limit)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cdr.dev/slog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	t.Parallel()
+	rules, err := parseIgnorePatterns(append(defaultIgnorePatterns,
+		"# comment",
+		"",
+		"*.zip",
+		"!keep.zip",
+		"build/",
+		"/docs",
+		"scripts/*.sh",
+		"**/testdata/*.json",
+	))
+	require.NoError(t, err)
+
+	cases := []struct {
+		Path     string
+		IsDir    bool
+		Excluded bool
+	}{
+		{Path: "main.tf", Excluded: false},
+		{Path: ".git", IsDir: true, Excluded: true},
+		{Path: ".terraform", IsDir: true, Excluded: true},
+		{Path: "modules/.terraform", IsDir: true, Excluded: true},
+		{Path: ".terraform.lock.hcl", Excluded: false},
+		{Path: "artifact.zip", Excluded: true},
+		{Path: "nested/artifact.zip", Excluded: true},
+		{Path: "keep.zip", Excluded: false},
+		{Path: "build", IsDir: true, Excluded: true},
+		{Path: "build", IsDir: false, Excluded: false},
+		{Path: "docs", IsDir: true, Excluded: true},
+		{Path: "nested/docs", IsDir: true, Excluded: false},
+		{Path: "scripts/setup.sh", Excluded: true},
+		{Path: "nested/scripts/setup.sh", Excluded: false},
+		{Path: "testdata/fixture.json", Excluded: true},
+		{Path: "a/b/testdata/fixture.json", Excluded: true},
+		{Path: "a/b/testdata/fixture.yaml", Excluded: false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.Excluded, rules.excluded(c.Path, c.IsDir), c.Path)
+	}
+
+	_, err = parseIgnorePatterns([]string{"[invalid"})
+	require.ErrorContains(t, err, "invalid exclude pattern")
+}
+
+func TestLoadIgnoreRules(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, terraformIgnoreFile), []byte("*.log\n!.git/\n"), 0o600))
+
+	rules, err := loadIgnoreRules(dir, []string{"*.tmp"})
+	require.NoError(t, err)
+	require.True(t, rules.excluded("debug.log", false))
+	require.True(t, rules.excluded("scratch.tmp", false))
+	require.True(t, rules.excluded(".terraform", true))
+	// Defaults can be overridden by the ignore file
+	require.False(t, rules.excluded(".git", true))
+
+	// The ignore file is optional
+	rules, err = loadIgnoreRules(t.TempDir(), nil)
+	require.NoError(t, err)
+	require.True(t, rules.excluded(".git", true))
+}
+
+func TestExcludedFilesNotHashedOrArchived(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	writeFile("main.tf", "resource {}")
+	writeFile("scripts/setup.sh", "echo hello")
+	rules, err := loadIgnoreRules(dir, []string{"*.zip"})
+	require.NoError(t, err)
+
+	before, err := computeDirectoryHash(dir, rules, false)
+	require.NoError(t, err)
+	writeFile(".terraform/providers/provider", "binary")
+	writeFile(".git/HEAD", "ref: refs/heads/main")
+	writeFile("build/artifact.zip", "zip")
+	after, err := computeDirectoryHash(dir, rules, false)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	var buf bytes.Buffer
+	err = tarDirectory(context.Background(), &buf, slog.Make(), dir, rules, 1<<20)
+	require.NoError(t, err)
+	var names []string
+	tr := tar.NewReader(&buf)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	require.Equal(t, []string{"build/", "main.tf", "scripts/", "scripts/setup.sh"}, names)
+
+	err = tarDirectory(context.Background(), io.Discard, slog.Make(), dir, rules, 5)
+	require.ErrorContains(t, err, "archive too big")
+
+	files, size, err := directorySize(dir, rules)
+	require.NoError(t, err)
+	require.Equal(t, 2, files)
+	require.Equal(t, int64(len("resource {}")+len("echo hello")), size)
+}
@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ImportInventoryDataSource{}
+
+func NewImportInventoryDataSource() datasource.DataSource {
+	return &ImportInventoryDataSource{}
+}
+
+// ImportInventoryDataSource defines the data source implementation.
+type ImportInventoryDataSource struct {
+	data *CoderdProviderData
+}
+
+// ImportInventoryDataSourceModel describes the data source data model.
+type ImportInventoryDataSourceModel struct {
+	Organizations []InventoryOrganization `tfsdk:"organizations"`
+	Users         []InventoryUser         `tfsdk:"users"`
+	Groups        []InventoryGroup        `tfsdk:"groups"`
+	Templates     []InventoryTemplate     `tfsdk:"templates"`
+}
+
+type InventoryOrganization struct {
+	ID        UUID         `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	IsDefault types.Bool   `tfsdk:"is_default"`
+}
+
+type InventoryUser struct {
+	ID       UUID         `tfsdk:"id"`
+	Username types.String `tfsdk:"username"`
+	Email    types.String `tfsdk:"email"`
+	ImportID types.String `tfsdk:"import_id"`
+}
+
+type InventoryGroup struct {
+	ID             UUID         `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	OrganizationID UUID         `tfsdk:"organization_id"`
+	Source         types.String `tfsdk:"source"`
+	ImportID       types.String `tfsdk:"import_id"`
+}
+
+type InventoryTemplate struct {
+	ID             UUID         `tfsdk:"id"`
+	Name           types.String `tfsdk:"name"`
+	OrganizationID UUID         `tfsdk:"organization_id"`
+	ImportID       types.String `tfsdk:"import_id"`
+}
+
+func (d *ImportInventoryDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_import_inventory"
+}
+
+func (d *ImportInventoryDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	idAttribute := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: description,
+			CustomType:          UUIDType,
+			Computed:            true,
+		}
+	}
+	importIDAttribute := func(resourceType string) schema.StringAttribute {
+		return schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("The ID to import the `%s` resource with.", resourceType),
+			Computed:            true,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An inventory of the organizations, users, groups and templates on the Coder deployment, with the IDs to import them with. " +
+			"It can be used to generate `import` blocks for an existing deployment, such as with `for_each`.",
+
+		Attributes: map[string]schema.Attribute{
+			"organizations": schema.ListNestedAttribute{
+				MarkdownDescription: "The organizations of the deployment.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": idAttribute("The ID of the organization."),
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the organization.",
+							Computed:            true,
+						},
+						"is_default": schema.BoolAttribute{
+							MarkdownDescription: "Whether the organization is the default organization.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"users": schema.ListNestedAttribute{
+				MarkdownDescription: "The users of the deployment.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": idAttribute("The ID of the user."),
+						"username": schema.StringAttribute{
+							MarkdownDescription: "The username of the user.",
+							Computed:            true,
+						},
+						"email": schema.StringAttribute{
+							MarkdownDescription: "The email of the user.",
+							Computed:            true,
+						},
+						"import_id": importIDAttribute("coderd_user"),
+					},
+				},
+			},
+			"groups": schema.ListNestedAttribute{
+				MarkdownDescription: "The groups in each of the organizations. Includes the `Everyone` group of each organization, whose ID is the ID of the organization.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id":              idAttribute("The ID of the group."),
+						"organization_id": idAttribute("The ID of the organization the group belongs to."),
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the group.",
+							Computed:            true,
+						},
+						"source": schema.StringAttribute{
+							MarkdownDescription: "How the group was created. Either `user`, or `oidc` for groups created by OIDC group sync.",
+							Computed:            true,
+						},
+						"import_id": importIDAttribute("coderd_group"),
+					},
+				},
+			},
+			"templates": schema.ListNestedAttribute{
+				MarkdownDescription: "The templates in each of the organizations.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id":              idAttribute("The ID of the template."),
+						"organization_id": idAttribute("The ID of the organization the template belongs to."),
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the template.",
+							Computed:            true,
+						},
+						"import_id": importIDAttribute("coderd_template"),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ImportInventoryDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *ImportInventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ImportInventoryDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	orgs, err := client.Organizations(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list organizations, got error: %s", err))
+		return
+	}
+	data.Organizations = make([]InventoryOrganization, 0, len(orgs))
+	data.Groups = []InventoryGroup{}
+	data.Templates = []InventoryTemplate{}
+	for _, org := range orgs {
+		data.Organizations = append(data.Organizations, InventoryOrganization{
+			ID:        UUIDValue(org.ID),
+			Name:      types.StringValue(org.Name),
+			IsDefault: types.BoolValue(org.IsDefault),
+		})
+
+		// Groups require an Enterprise license.
+		if d.data.Features[codersdk.FeatureTemplateRBAC].Enabled {
+			groups, err := client.GroupsByOrganization(ctx, org.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list groups in organization %q, got error: %s", org.Name, err))
+				return
+			}
+			for _, group := range groups {
+				data.Groups = append(data.Groups, InventoryGroup{
+					ID:             UUIDValue(group.ID),
+					Name:           types.StringValue(group.Name),
+					OrganizationID: UUIDValue(org.ID),
+					Source:         types.StringValue(string(group.Source)),
+					ImportID:       types.StringValue(org.Name + "/" + group.Name),
+				})
+			}
+		}
+
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{OrganizationID: org.ID})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list templates in organization %q, got error: %s", org.Name, err))
+			return
+		}
+		for _, template := range templates {
+			data.Templates = append(data.Templates, InventoryTemplate{
+				ID:             UUIDValue(template.ID),
+				Name:           types.StringValue(template.Name),
+				OrganizationID: UUIDValue(org.ID),
+				ImportID:       types.StringValue(org.Name + "/" + template.Name),
+			})
+		}
+	}
+
+	users, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.User, error) {
+		res, err := client.Users(ctx, codersdk.UsersRequest{
+			Pagination: page,
+		})
+		return res.Users, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list users, got error: %s", err))
+		return
+	}
+	data.Users = make([]InventoryUser, 0, len(users))
+	for _, user := range users {
+		data.Users = append(data.Users, InventoryUser{
+			ID:       UUIDValue(user.ID),
+			Username: types.StringValue(user.Username),
+			Email:    types.StringValue(user.Email),
+			ImportID: types.StringValue(user.Username),
+		})
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccImportInventoryDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "import_inventory_data_acc", false)
+	firstUser, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	cfg := testAccImportInventoryDataSourceConfig{
+		URL:   client.URL.String(),
+		Token: client.SessionToken(),
+	}
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "organizations.#", "1"),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "organizations.0.id", firstUser.OrganizationIDs[0].String()),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "organizations.0.is_default", "true"),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "users.0.id", firstUser.ID.String()),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "users.0.import_id", firstUser.Username),
+					resource.TestCheckResourceAttr("data.coderd_import_inventory.test", "templates.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+type testAccImportInventoryDataSourceConfig struct {
+	URL   string
+	Token string
+}
+
+func (c testAccImportInventoryDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_import_inventory" "test" {}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("importInventoryDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
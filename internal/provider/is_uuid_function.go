@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &IsUUIDFunction{}
+
+func NewIsUUIDFunction() function.Function {
+	return &IsUUIDFunction{}
+}
+
+// IsUUIDFunction defines the function implementation.
+type IsUUIDFunction struct{}
+
+func (f *IsUUIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_uuid"
+}
+
+func (f *IsUUIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Check whether a string is a UUID",
+		MarkdownDescription: "Returns whether a string is a UUID, such as the ID of a Coder object, rather than a name or an `<organization-name>/<name>` import ID.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "s",
+				MarkdownDescription: "The string to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsUUIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var s string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &s))
+	if resp.Error != nil {
+		return
+	}
+
+	_, err := uuid.Parse(s)
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, err == nil))
+}
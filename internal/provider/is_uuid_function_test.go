@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsUUIDFunction(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		s        string
+		expected bool
+	}{
+		{name: "UUID", s: "6a8f8f5c-3f0b-4a5b-8c3e-1f2d3c4b5a69", expected: true},
+		{name: "Name", s: "my-template", expected: false},
+		{name: "OrgAndName", s: "default/my-template", expected: false},
+		{name: "Empty", s: "", expected: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.BoolUnknown()),
+			}
+			NewIsUUIDFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(c.s)}),
+			}, resp)
+			require.Nil(t, resp.Error)
+			require.Equal(t, types.BoolValue(c.expected), resp.Result.Value())
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// reservedProvisionerTags are set by Coder from who pushes a template version
+// or creates a provisioner key, so can't be composed in configs.
+var reservedProvisionerTags = []string{"owner", "scope"}
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &MergeProvisionerTagsFunction{}
+
+func NewMergeProvisionerTagsFunction() function.Function {
+	return &MergeProvisionerTagsFunction{}
+}
+
+// MergeProvisionerTagsFunction defines the function implementation.
+type MergeProvisionerTagsFunction struct{}
+
+func (f *MergeProvisionerTagsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "merge_provisioner_tags"
+}
+
+func (f *MergeProvisionerTagsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merge provisioner tag sets",
+		MarkdownDescription: "Merges maps of provisioner tags, such as the `provisioner_tags` of template versions, with tags in later maps taking precedence. " +
+			"Leading and trailing whitespace is trimmed from keys and values. " +
+			fmt.Sprintf("Fails if a key is empty, or is one of the tags reserved by Coder: `%s`.", strings.Join(reservedProvisionerTags, "`, `")),
+		VariadicParameter: function.MapParameter{
+			Name:                "tags",
+			MarkdownDescription: "The maps of tags to merge. Null maps are skipped.",
+			ElementType:         types.StringType,
+			AllowNullValue:      true,
+		},
+		Return: function.MapReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *MergeProvisionerTagsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var tagSets []map[string]string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &tagSets))
+	if resp.Error != nil {
+		return
+	}
+
+	merged := map[string]string{}
+	for i, tags := range tagSets {
+		for key, value := range tags {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				resp.Error = function.NewArgumentFuncError(int64(i), "Provisioner tag keys can't be empty")
+				return
+			}
+			if slices.Contains(reservedProvisionerTags, key) {
+				resp.Error = function.NewArgumentFuncError(int64(i), fmt.Sprintf("Provisioner tag %q is reserved, and is set by Coder", key))
+				return
+			}
+			merged[key] = strings.TrimSpace(value)
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, merged))
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeProvisionerTagsFunction(t *testing.T) {
+	t.Parallel()
+	tags := func(m map[string]string) attr.Value {
+		if m == nil {
+			return types.MapNull(types.StringType)
+		}
+		elems := make(map[string]attr.Value, len(m))
+		for key, value := range m {
+			elems[key] = types.StringValue(value)
+		}
+		return types.MapValueMust(types.StringType, elems)
+	}
+
+	cases := []struct {
+		name     string
+		tagSets  []map[string]string
+		expected map[string]string
+		err      string
+	}{
+		{
+			name:     "None",
+			expected: map[string]string{},
+		},
+		{
+			name: "LaterTakePrecedence",
+			tagSets: []map[string]string{
+				{"region": "us-east", "arch": "amd64"},
+				nil,
+				{"region": "eu-west"},
+			},
+			expected: map[string]string{"region": "eu-west", "arch": "amd64"},
+		},
+		{
+			name:     "Normalized",
+			tagSets:  []map[string]string{{" region ": " us-east\n"}},
+			expected: map[string]string{"region": "us-east"},
+		},
+		{
+			name:    "EmptyKey",
+			tagSets: []map[string]string{{" ": "value"}},
+			err:     "can't be empty",
+		},
+		{
+			name:    "Reserved",
+			tagSets: []map[string]string{{"region": "us-east"}, {"owner": "alice"}},
+			err:     "reserved",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			elems := make([]attr.Value, 0, len(c.tagSets))
+			elemTypes := make([]attr.Type, 0, len(c.tagSets))
+			for _, tagSet := range c.tagSets {
+				elems = append(elems, tags(tagSet))
+				elemTypes = append(elemTypes, types.MapType{ElemType: types.StringType})
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.MapUnknown(types.StringType)),
+			}
+			NewMergeProvisionerTagsFunction().Run(ctx, function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.TupleValueMust(elemTypes, elems)}),
+			}, resp)
+			if c.err != "" {
+				require.NotNil(t, resp.Error)
+				require.Contains(t, resp.Error.Error(), c.err)
+				return
+			}
+			require.Nil(t, resp.Error)
+			require.True(t, tags(c.expected).Equal(resp.Result.Value()), "got %s", resp.Result.Value())
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &OrgAndNameFunction{}
+
+func NewOrgAndNameFunction() function.Function {
+	return &OrgAndNameFunction{}
+}
+
+// OrgAndNameFunction defines the function implementation.
+type OrgAndNameFunction struct{}
+
+var orgAndNameAttrTypes = map[string]attr.Type{
+	"organization_name": types.StringType,
+	"name":              types.StringType,
+}
+
+func (f *OrgAndNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "org_and_name"
+}
+
+func (f *OrgAndNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split an `<organization-name>/<name>` ID",
+		MarkdownDescription: "Splits an `<organization-name>/<name>` ID, as used to import groups and templates, into an object with " +
+			"`organization_name` and `name` attributes. Fails if the ID isn't of that form.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "s",
+				MarkdownDescription: "The ID to split.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: orgAndNameAttrTypes,
+		},
+	}
+}
+
+func (f *OrgAndNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var s string
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &s))
+	if resp.Error != nil {
+		return
+	}
+
+	orgName, name, ok := splitOrgAndName(s)
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Invalid ID %q, expected `<organization-name>/<name>`", s))
+		return
+	}
+
+	result, diags := types.ObjectValue(orgAndNameAttrTypes, map[string]attr.Value{
+		"organization_name": types.StringValue(orgName),
+		"name":              types.StringValue(name),
+	})
+	resp.Error = function.ConcatFuncErrors(resp.Error, function.FuncErrorFromDiags(ctx, diags))
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+// splitOrgAndName splits an `<organization-name>/<name>` ID, returning false
+// if either part is empty or there are more than two parts.
+func splitOrgAndName(s string) (string, string, bool) {
+	orgName, name, ok := strings.Cut(s, "/")
+	if !ok || orgName == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return orgName, name, true
+}
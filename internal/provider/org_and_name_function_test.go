@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgAndNameFunction(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		s       string
+		orgName string
+		objName string
+		err     bool
+	}{
+		{name: "Valid", s: "default/my-template", orgName: "default", objName: "my-template"},
+		{name: "NoOrganization", s: "my-template", err: true},
+		{name: "EmptyOrganization", s: "/my-template", err: true},
+		{name: "EmptyName", s: "default/", err: true},
+		{name: "TooManyParts", s: "default/my-template/v1", err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ObjectUnknown(orgAndNameAttrTypes)),
+			}
+			NewOrgAndNameFunction().Run(context.Background(), function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(c.s)}),
+			}, resp)
+			if c.err {
+				require.NotNil(t, resp.Error)
+				return
+			}
+			require.Nil(t, resp.Error)
+			expected := types.ObjectValueMust(orgAndNameAttrTypes, map[string]attr.Value{
+				"organization_name": types.StringValue(c.orgName),
+				"name":              types.StringValue(c.objName),
+			})
+			require.Equal(t, expected, resp.Result.Value())
+		})
+	}
+}
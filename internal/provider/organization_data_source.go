@@ -135,7 +135,7 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 			return
 		}
 	} else if data.IsDefault.ValueBool() { // Get Default
-		org, err = client.OrganizationByName(ctx, "default")
+		org, err = d.data.organizationByName(ctx, "default")
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get default organization, got error: %s", err))
 			return
@@ -145,7 +145,7 @@ func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRe
 			return
 		}
 	} else { // By Name
-		org, err = client.OrganizationByName(ctx, data.Name.ValueString())
+		org, err = d.data.organizationByName(ctx, data.Name.ValueString())
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get organization by name, got error: %s", err))
 			return
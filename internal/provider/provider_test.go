@@ -1,10 +1,21 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/stretchr/testify/require"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -20,3 +31,79 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func TestReadTokenFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("abc-123\n"), 0o600))
+	token, err := readTokenFile(tokenFile)
+	require.NoError(t, err)
+	require.Equal(t, "abc-123", token)
+
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte("\n"), 0o600))
+	_, err = readTokenFile(emptyFile)
+	require.ErrorContains(t, err, "is empty")
+
+	_, err = readTokenFile(filepath.Join(dir, "missing"))
+	require.ErrorContains(t, err, "failed to read token file")
+}
+
+func TestAllFeaturesEnabled(t *testing.T) {
+	t.Parallel()
+	features := allFeaturesEnabled()
+	require.True(t, features[codersdk.FeatureTemplateRBAC].Enabled)
+	require.True(t, features[codersdk.FeatureWorkspaceProxy].Enabled)
+	require.False(t, CheckGroupEntitlements(context.Background(), features).HasError())
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "terraform-provider-coderd/1.0.0", userAgent("1.0.0", ""))
+	require.Equal(t, "terraform-provider-coderd/1.0.0 pipeline/deploy-prod", userAgent("1.0.0", "pipeline/deploy-prod"))
+}
+
+func TestImpersonate(t *testing.T) {
+	t.Parallel()
+	var names []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/users/alice/keys/tokens":
+			var req codersdk.CreateTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			names = append(names, req.TokenName)
+			if !strings.HasPrefix(req.TokenName, impersonationTokenPrefix) || req.Lifetime != impersonationTokenLifetime+10*time.Minute {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(codersdk.GenerateAPIKeyResponse{Key: "alice-token"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(codersdk.Response{Message: "not found"})
+		}
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client := codersdk.New(srvURL)
+
+	token, err := impersonate(context.Background(), client, "alice", 10*time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "alice-token", token)
+	// Each run creates a token with a unique name, rather than replacing
+	// another run's token.
+	_, err = impersonate(context.Background(), client, "alice", 10*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+	require.NotEqual(t, names[0], names[1])
+
+	_, err = impersonate(context.Background(), client, "bob", 10*time.Minute)
+	require.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RegionsDataSource{}
+
+func NewRegionsDataSource() datasource.DataSource {
+	return &RegionsDataSource{}
+}
+
+// RegionsDataSource defines the data source implementation.
+type RegionsDataSource struct {
+	data *CoderdProviderData
+}
+
+// RegionsDataSourceModel describes the data source data model.
+type RegionsDataSourceModel struct {
+	Regions []Region `tfsdk:"regions"`
+}
+
+type Region struct {
+	ID               UUID         `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	DisplayName      types.String `tfsdk:"display_name"`
+	IconURL          types.String `tfsdk:"icon_url"`
+	Healthy          types.Bool   `tfsdk:"healthy"`
+	PathAppURL       types.String `tfsdk:"path_app_url"`
+	WildcardHostname types.String `tfsdk:"wildcard_hostname"`
+}
+
+func (d *RegionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regions"
+}
+
+func (d *RegionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The regions workspace applications can be accessed from. " +
+			"This includes the primary region served by the Coder deployment itself, and any workspace proxies.",
+
+		Attributes: map[string]schema.Attribute{
+			"regions": schema.ListNestedAttribute{
+				MarkdownDescription: "The regions of the deployment. The primary region is always listed first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the region. For workspace proxies, this is the ID of the proxy.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the region.",
+							Computed:            true,
+						},
+						"display_name": schema.StringAttribute{
+							MarkdownDescription: "The display name of the region.",
+							Computed:            true,
+						},
+						"icon_url": schema.StringAttribute{
+							MarkdownDescription: "Relative path or external URL of the region's icon.",
+							Computed:            true,
+						},
+						"healthy": schema.BoolAttribute{
+							MarkdownDescription: "Whether the region is reachable and healthy.",
+							Computed:            true,
+						},
+						"path_app_url": schema.StringAttribute{
+							MarkdownDescription: "The URL path-based workspace applications are served from.",
+							Computed:            true,
+						},
+						"wildcard_hostname": schema.StringAttribute{
+							MarkdownDescription: "The wildcard hostname subdomain-based workspace applications are served from, e.g. `*.coder.example.com`. Empty if subdomain applications are not configured.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RegionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *RegionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RegionsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	regions, err := client.Regions(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get regions, got error: %s", err))
+		return
+	}
+
+	tfRegions := make([]Region, 0, len(regions))
+	for _, region := range regions {
+		tfRegions = append(tfRegions, Region{
+			ID:               UUIDValue(region.ID),
+			Name:             types.StringValue(region.Name),
+			DisplayName:      types.StringValue(region.DisplayName),
+			IconURL:          types.StringValue(region.IconURL),
+			Healthy:          types.BoolValue(region.Healthy),
+			PathAppURL:       types.StringValue(region.PathAppURL),
+			WildcardHostname: types.StringValue(region.WildcardHostname),
+		})
+	}
+	data.Regions = tfRegions
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccRegionsDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "regions_data_acc", false)
+
+	cfg := testAccRegionsDataSourceConfig{
+		URL:   client.URL.String(),
+		Token: client.SessionToken(),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.coderd_regions.test", "regions.#", "1"),
+					resource.TestCheckResourceAttr("data.coderd_regions.test", "regions.0.name", "primary"),
+					resource.TestCheckResourceAttr("data.coderd_regions.test", "regions.0.healthy", "true"),
+					resource.TestCheckResourceAttrSet("data.coderd_regions.test", "regions.0.id"),
+					resource.TestCheckResourceAttrSet("data.coderd_regions.test", "regions.0.path_app_url"),
+				),
+			},
+		},
+	})
+}
+
+type testAccRegionsDataSourceConfig struct {
+	URL   string
+	Token string
+}
+
+func (c testAccRegionsDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_regions" "test" {}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("regionsDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ReplicasDataSource{}
+
+func NewReplicasDataSource() datasource.DataSource {
+	return &ReplicasDataSource{}
+}
+
+// ReplicasDataSource defines the data source implementation.
+type ReplicasDataSource struct {
+	data *CoderdProviderData
+}
+
+// ReplicasDataSourceModel describes the data source data model.
+type ReplicasDataSourceModel struct {
+	Replicas []Replica `tfsdk:"replicas"`
+}
+
+type Replica struct {
+	ID              UUID         `tfsdk:"id"`
+	Hostname        types.String `tfsdk:"hostname"`
+	CreatedAt       types.Int64  `tfsdk:"created_at"`
+	RelayAddress    types.String `tfsdk:"relay_address"`
+	RegionID        types.Int64  `tfsdk:"region_id"`
+	Error           types.String `tfsdk:"error"`
+	DatabaseLatency types.Int64  `tfsdk:"database_latency"`
+}
+
+func (d *ReplicasDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_replicas"
+}
+
+func (d *ReplicasDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The coderd replicas of a highly available Coder deployment.",
+
+		Attributes: map[string]schema.Attribute{
+			"replicas": schema.ListNestedAttribute{
+				MarkdownDescription: "The replicas serving the deployment.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the replica.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"hostname": schema.StringAttribute{
+							MarkdownDescription: "The hostname of the machine the replica is running on.",
+							Computed:            true,
+						},
+						"created_at": schema.Int64Attribute{
+							MarkdownDescription: "Unix timestamp of when the replica started.",
+							Computed:            true,
+						},
+						"relay_address": schema.StringAttribute{
+							MarkdownDescription: "The address other replicas use to relay DERP traffic to this replica.",
+							Computed:            true,
+						},
+						"region_id": schema.Int64Attribute{
+							MarkdownDescription: "The ID of the DERP region the replica belongs to.",
+							Computed:            true,
+						},
+						"error": schema.StringAttribute{
+							MarkdownDescription: "The error the replica encountered when connecting to its peers. Empty if the replica is healthy.",
+							Computed:            true,
+						},
+						"database_latency": schema.Int64Attribute{
+							MarkdownDescription: "The latency of the replica's connection to the database, in microseconds.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ReplicasDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *ReplicasDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ReplicasDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	replicas, err := client.Replicas(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get replicas, got error: %s", err))
+		return
+	}
+
+	tfReplicas := make([]Replica, 0, len(replicas))
+	for _, replica := range replicas {
+		tfReplicas = append(tfReplicas, Replica{
+			ID:              UUIDValue(replica.ID),
+			Hostname:        types.StringValue(replica.Hostname),
+			CreatedAt:       types.Int64Value(replica.CreatedAt.Unix()),
+			RelayAddress:    types.StringValue(replica.RelayAddress),
+			RegionID:        types.Int64Value(int64(replica.RegionID)),
+			Error:           types.StringValue(replica.Error),
+			DatabaseLatency: types.Int64Value(int64(replica.DatabaseLatency)),
+		})
+	}
+	data.Replicas = tfReplicas
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SSHKeyDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &SSHKeyDataSource{}
+
+func NewSSHKeyDataSource() datasource.DataSource {
+	return &SSHKeyDataSource{}
+}
+
+// SSHKeyDataSource defines the data source implementation.
+type SSHKeyDataSource struct {
+	data *CoderdProviderData
+}
+
+// SSHKeyDataSourceModel describes the data source data model.
+type SSHKeyDataSourceModel struct {
+	// Exactly one of UserID or Username must be set.
+	UserID   UUID         `tfsdk:"user_id"`
+	Username types.String `tfsdk:"username"`
+
+	PublicKey types.String `tfsdk:"public_key"`
+	CreatedAt types.Int64  `tfsdk:"created_at"`
+	UpdatedAt types.Int64  `tfsdk:"updated_at"`
+}
+
+func (d *SSHKeyDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_key"
+}
+
+func (d *SSHKeyDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The public key of the Git SSH key Coder generates for a user. " +
+			"Workspaces authenticate to Git providers with this key.",
+
+		Attributes: map[string]schema.Attribute{
+			"user_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the user to retrieve the SSH key of. This field will be populated if a username is supplied.",
+				CustomType:          UUIDType,
+				Optional:            true,
+				Computed:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username of the user to retrieve the SSH key of. This field will be populated if a user ID is supplied.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"public_key": schema.StringAttribute{
+				MarkdownDescription: "The public key, in OpenSSH authorized keys format.",
+				Computed:            true,
+			},
+			"created_at": schema.Int64Attribute{
+				MarkdownDescription: "Unix timestamp of when the key was created.",
+				Computed:            true,
+			},
+			"updated_at": schema.Int64Attribute{
+				MarkdownDescription: "Unix timestamp of when the key was last regenerated.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SSHKeyDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *SSHKeyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SSHKeyDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	var ident string
+	if !data.UserID.IsNull() {
+		ident = data.UserID.ValueString()
+	} else {
+		ident = data.Username.ValueString()
+	}
+	user, err := client.User(ctx, ident)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get user, got error: %s", err))
+		return
+	}
+
+	key, err := client.GitSSHKey(ctx, user.ID.String())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get user SSH key, got error: %s", err))
+		return
+	}
+
+	data.UserID = UUIDValue(user.ID)
+	data.Username = types.StringValue(user.Username)
+	data.PublicKey = types.StringValue(key.PublicKey)
+	data.CreatedAt = types.Int64Value(key.CreatedAt.Unix())
+	data.UpdatedAt = types.Int64Value(key.UpdatedAt.Unix())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *SSHKeyDataSource) ConfigValidators(context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("user_id"),
+			path.MatchRoot("username"),
+		),
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccSSHKeyDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "ssh_key_data_acc", false)
+	firstUser, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	key, err := client.GitSSHKey(ctx, firstUser.ID.String())
+	require.NoError(t, err)
+
+	checkFn := resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("data.coderd_ssh_key.test", "user_id", firstUser.ID.String()),
+		resource.TestCheckResourceAttr("data.coderd_ssh_key.test", "username", firstUser.Username),
+		resource.TestCheckResourceAttr("data.coderd_ssh_key.test", "public_key", key.PublicKey),
+		resource.TestCheckResourceAttrSet("data.coderd_ssh_key.test", "created_at"),
+		resource.TestCheckResourceAttrSet("data.coderd_ssh_key.test", "updated_at"),
+	)
+
+	t.Run("KeyByUserIDOk", func(t *testing.T) {
+		cfg := testAccSSHKeyDataSourceConfig{
+			URL:    client.URL.String(),
+			Token:  client.SessionToken(),
+			UserID: PtrTo(firstUser.ID.String()),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check:  checkFn,
+				},
+			},
+		})
+	})
+
+	t.Run("KeyByUsernameOk", func(t *testing.T) {
+		cfg := testAccSSHKeyDataSourceConfig{
+			URL:      client.URL.String(),
+			Token:    client.SessionToken(),
+			Username: PtrTo(firstUser.Username),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check:  checkFn,
+				},
+			},
+		})
+	})
+
+	t.Run("NeitherUserIDNorUsernameError", func(t *testing.T) {
+		cfg := testAccSSHKeyDataSourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config:      cfg.String(t),
+					ExpectError: regexp.MustCompile(`Exactly one of these attributes must be configured: \[user\_id,username\]`),
+				},
+			},
+		})
+	})
+}
+
+type testAccSSHKeyDataSourceConfig struct {
+	URL   string
+	Token string
+
+	UserID   *string
+	Username *string
+}
+
+func (c testAccSSHKeyDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_ssh_key" "test" {
+	user_id  = {{orNull .UserID}}
+	username = {{orNull .Username}}
+}
+`
+
+	funcMap := template.FuncMap{
+		"orNull": PrintOrNull,
+	}
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("sshKeyDataSource").Funcs(funcMap).Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &StarterTemplatesDataSource{}
+
+func NewStarterTemplatesDataSource() datasource.DataSource {
+	return &StarterTemplatesDataSource{}
+}
+
+// StarterTemplatesDataSource defines the data source implementation.
+type StarterTemplatesDataSource struct {
+	data *CoderdProviderData
+}
+
+// StarterTemplatesDataSourceModel describes the data source data model.
+type StarterTemplatesDataSourceModel struct {
+	OrganizationID UUID `tfsdk:"organization_id"`
+
+	StarterTemplates []StarterTemplate `tfsdk:"starter_templates"`
+}
+
+type StarterTemplate struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Icon        types.String `tfsdk:"icon"`
+	SourceURL   types.String `tfsdk:"source_url"`
+	Tags        types.Set    `tfsdk:"tags"`
+}
+
+func (d *StarterTemplatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_starter_templates"
+}
+
+func (d *StarterTemplatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The built-in starter templates shipped with the Coder deployment.",
+
+		Attributes: map[string]schema.Attribute{
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The organization ID to list starter templates for. Defaults to the provider default organization ID.",
+				CustomType:          UUIDType,
+				Optional:            true,
+				Computed:            true,
+			},
+			"starter_templates": schema.ListNestedAttribute{
+				MarkdownDescription: "The starter templates available on the deployment.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the starter template, e.g. `docker`.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The display name of the starter template.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "A description of the starter template.",
+							Computed:            true,
+						},
+						"icon": schema.StringAttribute{
+							MarkdownDescription: "Relative path or external URL of the starter template's icon.",
+							Computed:            true,
+						},
+						"source_url": schema.StringAttribute{
+							MarkdownDescription: "URL of the web page with the source of the starter template, such as its directory in the Coder repository on GitHub. It isn't a download URL for an archive of the template.",
+							Computed:            true,
+						},
+						"tags": schema.SetAttribute{
+							MarkdownDescription: "Tags associated with the starter template.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *StarterTemplatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *StarterTemplatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data StarterTemplatesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	if data.OrganizationID.IsNull() {
+		data.OrganizationID = UUIDValue(d.data.DefaultOrganizationID)
+	}
+
+	examples, err := client.TemplateExamples(ctx, data.OrganizationID.ValueUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get starter templates, got error: %s", err))
+		return
+	}
+
+	starterTemplates := make([]StarterTemplate, 0, len(examples))
+	for _, example := range examples {
+		tags := make([]attr.Value, 0, len(example.Tags))
+		for _, tag := range example.Tags {
+			tags = append(tags, types.StringValue(tag))
+		}
+		starterTemplates = append(starterTemplates, StarterTemplate{
+			ID:          types.StringValue(example.ID),
+			Name:        types.StringValue(example.Name),
+			Description: types.StringValue(example.Description),
+			Icon:        types.StringValue(example.Icon),
+			SourceURL:   types.StringValue(example.URL),
+			Tags:        types.SetValueMust(types.StringType, tags),
+		})
+	}
+	data.StarterTemplates = starterTemplates
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
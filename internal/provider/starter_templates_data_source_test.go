@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccStarterTemplatesDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "starter_templates_data_acc", false)
+	firstUser, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+
+	cfg := testAccStarterTemplatesDataSourceConfig{
+		URL:   client.URL.String(),
+		Token: client.SessionToken(),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.coderd_starter_templates.test", "organization_id", firstUser.OrganizationIDs[0].String()),
+					resource.TestMatchTypeSetElemNestedAttrs("data.coderd_starter_templates.test", "starter_templates.*", map[string]*regexp.Regexp{
+						"id":         regexp.MustCompile("^docker$"),
+						"name":       regexp.MustCompile(".+"),
+						"source_url": regexp.MustCompile("^https://"),
+					}),
+				),
+			},
+		},
+	})
+}
+
+type testAccStarterTemplatesDataSourceConfig struct {
+	URL   string
+	Token string
+
+	OrganizationID *string
+}
+
+func (c testAccStarterTemplatesDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_starter_templates" "test" {
+	organization_id = {{orNull .OrganizationID}}
+}
+`
+
+	funcMap := template.FuncMap{
+		"orNull": PrintOrNull,
+	}
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("starterTemplatesDataSource").Funcs(funcMap).Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// schemaVersion is the version of the group, template and user resource
+// schemas. Increment it when attributes are removed, or added with defaults,
+// so states written by earlier versions of the provider are upgraded.
+const schemaVersion = 1
+
+// stateUpgraders returns upgraders from every earlier schema version of the
+// resource to the current one.
+func stateUpgraders(ctx context.Context, r resource.Resource) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	upgraders := make(map[int64]resource.StateUpgrader, schemaVersion)
+	for version := int64(0); version < schemaVersion; version++ {
+		upgraders[version] = jsonStateUpgrader(schemaResp.Schema)
+	}
+	return upgraders
+}
+
+// jsonStateUpgrader returns an upgrader that rewrites the raw state for the
+// current schema. Attributes that were removed are dropped, and attributes
+// with defaults that are missing from the state are set to their default, so
+// they aren't planned as changing after upgrading.
+func jsonStateUpgrader(current schema.Schema) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			if req.RawState == nil {
+				resp.Diagnostics.AddError("State Upgrade Error", "The prior state is empty.")
+				return
+			}
+			// Decode numbers as written, so large integers keep their precision.
+			decoder := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+			decoder.UseNumber()
+			var state map[string]any
+			if err := decoder.Decode(&state); err != nil {
+				resp.Diagnostics.AddError("State Upgrade Error", fmt.Sprintf("Failed to decode the prior state: %s", err))
+				return
+			}
+			for name, attr := range current.Attributes {
+				if _, ok := state[name]; ok {
+					continue
+				}
+				value, ok := attributeDefault(ctx, name, attr)
+				if ok {
+					state[name] = value
+				}
+			}
+			pruneState(state, current.Type().TerraformType(ctx))
+			upgraded, err := json.Marshal(state)
+			if err != nil {
+				resp.Diagnostics.AddError("State Upgrade Error", fmt.Sprintf("Failed to encode the upgraded state: %s", err))
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+// attributeDefault returns the static default of a primitive attribute, if
+// it has one.
+func attributeDefault(ctx context.Context, name string, attr schema.Attribute) (any, bool) {
+	attrPath := path.Root(name)
+	switch attr := attr.(type) {
+	case schema.StringAttribute:
+		if attr.Default == nil {
+			return nil, false
+		}
+		var resp defaults.StringResponse
+		attr.Default.DefaultString(ctx, defaults.StringRequest{Path: attrPath}, &resp)
+		return resp.PlanValue.ValueString(), !resp.Diagnostics.HasError() && !resp.PlanValue.IsNull()
+	case schema.BoolAttribute:
+		if attr.Default == nil {
+			return nil, false
+		}
+		var resp defaults.BoolResponse
+		attr.Default.DefaultBool(ctx, defaults.BoolRequest{Path: attrPath}, &resp)
+		return resp.PlanValue.ValueBool(), !resp.Diagnostics.HasError() && !resp.PlanValue.IsNull()
+	case schema.Int64Attribute:
+		if attr.Default == nil {
+			return nil, false
+		}
+		var resp defaults.Int64Response
+		attr.Default.DefaultInt64(ctx, defaults.Int64Request{Path: attrPath}, &resp)
+		return resp.PlanValue.ValueInt64(), !resp.Diagnostics.HasError() && !resp.PlanValue.IsNull()
+	case schema.Int32Attribute:
+		if attr.Default == nil {
+			return nil, false
+		}
+		var resp defaults.Int32Response
+		attr.Default.DefaultInt32(ctx, defaults.Int32Request{Path: attrPath}, &resp)
+		return resp.PlanValue.ValueInt32(), !resp.Diagnostics.HasError() && !resp.PlanValue.IsNull()
+	default:
+		return nil, false
+	}
+}
+
+// pruneState removes the attributes of objects in the decoded state that
+// aren't in their type, including in nested objects.
+func pruneState(value any, typ tftypes.Type) {
+	switch typ := typ.(type) {
+	case tftypes.Object:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		for name, attrValue := range obj {
+			attrType, ok := typ.AttributeTypes[name]
+			if !ok {
+				delete(obj, name)
+				continue
+			}
+			pruneState(attrValue, attrType)
+		}
+	case tftypes.List:
+		pruneElements(value, typ.ElementType)
+	case tftypes.Set:
+		pruneElements(value, typ.ElementType)
+	case tftypes.Map:
+		if obj, ok := value.(map[string]any); ok {
+			for _, elem := range obj {
+				pruneState(elem, typ.ElementType)
+			}
+		}
+	}
+}
+
+func pruneElements(value any, typ tftypes.Type) {
+	if elems, ok := value.([]any); ok {
+		for _, elem := range elems {
+			pruneState(elem, typ)
+		}
+	}
+}
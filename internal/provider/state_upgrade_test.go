@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONStateUpgrader(t *testing.T) {
+	t.Parallel()
+	current := schema.Schema{
+		Version: schemaVersion,
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"mode": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("exact"),
+			},
+			"adopt": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"millis": schema.Int64Attribute{
+				Optional: true,
+			},
+			"items": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}
+	prior := `{
+		"name": "example",
+		"adopt": true,
+		"millis": 9007199254740993,
+		"removed": "value",
+		"items": [{"id": "a", "removed": true}]
+	}`
+
+	upgrader := jsonStateUpgrader(current)
+	var resp resource.UpgradeStateResponse
+	upgrader.StateUpgrader(context.Background(), resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte(prior)},
+	}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.NotNil(t, resp.DynamicValue)
+	require.JSONEq(t, `{
+		"name": "example",
+		"mode": "exact",
+		"adopt": true,
+		"millis": 9007199254740993,
+		"items": [{"id": "a"}]
+	}`, string(resp.DynamicValue.JSON))
+
+	// The upgraded state can be decoded with the current schema
+	_, err := resp.DynamicValue.Unmarshal(current.Type().TerraformType(context.Background()))
+	require.NoError(t, err)
+
+	resp = resource.UpgradeStateResponse{}
+	upgrader.StateUpgrader(context.Background(), resource.UpgradeStateRequest{
+		RawState: &tfprotov6.RawState{JSON: []byte("not json")},
+	}, &resp)
+	require.True(t, resp.Diagnostics.HasError())
+}
+
+func TestStateUpgraders(t *testing.T) {
+	t.Parallel()
+	for _, r := range []resource.Resource{NewGroupResource(), NewUserResource(), NewTemplateResource()} {
+		upgraders := stateUpgraders(context.Background(), r)
+		require.Len(t, upgraders, schemaVersion)
+		for version := int64(0); version < schemaVersion; version++ {
+			require.Contains(t, upgraders, version)
+		}
+	}
+}
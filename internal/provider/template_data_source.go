@@ -66,6 +66,13 @@ type TemplateDataSourceModel struct {
 	ACL types.Object `tfsdk:"acl"`
 }
 
+// templateDataSourceACLTypeAttr is the type schema for the ACL of a template
+// data source, which has no `mode`.
+var templateDataSourceACLTypeAttr = map[string]attr.Type{
+	"users":  permissionTypeAttr,
+	"groups": permissionTypeAttr,
+}
+
 func (d *TemplateDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_template"
 }
@@ -283,8 +290,14 @@ func (d *TemplateDataSource) Read(ctx context.Context, req datasource.ReadReques
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template ACL: %s", err))
 		return
 	}
-	tfACL := convertResponseToACL(acl)
-	aclObj, diag := types.ObjectValueFrom(ctx, aclTypeAttr, tfACL)
+	tfACL := convertResponseToACL(acl, types.StringNull())
+	aclObj, diag := types.ObjectValueFrom(ctx, templateDataSourceACLTypeAttr, struct {
+		UserPermissions  []Permission `tfsdk:"users"`
+		GroupPermissions []Permission `tfsdk:"groups"`
+	}{
+		UserPermissions:  tfACL.UserPermissions,
+		GroupPermissions: tfACL.GroupPermissions,
+	})
 	if diag.HasError() {
 		resp.Diagnostics.Append(diag...)
 		return
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// templateDAUsDefaultDays is the number of days reported on if no start date
+// is set. It's a whole number of weeks, so it suits both intervals.
+const templateDAUsDefaultDays = 28
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TemplateDAUsDataSource{}
+
+func NewTemplateDAUsDataSource() datasource.DataSource {
+	return &TemplateDAUsDataSource{}
+}
+
+// TemplateDAUsDataSource defines the data source implementation.
+type TemplateDAUsDataSource struct {
+	data *CoderdProviderData
+}
+
+// TemplateDAUsDataSourceModel describes the data source data model.
+type TemplateDAUsDataSourceModel struct {
+	TemplateID   UUID         `tfsdk:"template_id"`
+	Interval     types.String `tfsdk:"interval"`
+	StartDate    types.String `tfsdk:"start_date"`
+	EndDate      types.String `tfsdk:"end_date"`
+	TZHourOffset types.Int64  `tfsdk:"tz_hour_offset"`
+
+	Entries []TemplateDAUsEntry `tfsdk:"entries"`
+}
+
+type TemplateDAUsEntry struct {
+	StartDate   types.String `tfsdk:"start_date"`
+	EndDate     types.String `tfsdk:"end_date"`
+	ActiveUsers types.Int64  `tfsdk:"active_users"`
+}
+
+func (d *TemplateDAUsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template_daus"
+}
+
+func (d *TemplateDAUsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The number of unique users of a template in each day or week of a date range, from the template insights API.",
+
+		Attributes: map[string]schema.Attribute{
+			"template_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template to retrieve active users for.",
+				CustomType:          UUIDType,
+				Required:            true,
+			},
+			"interval": schema.StringAttribute{
+				MarkdownDescription: "The interval to count users in. Either `day` or `week`. Defaults to `day`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(codersdk.InsightsReportIntervalDay), string(codersdk.InsightsReportIntervalWeek)),
+				},
+			},
+			"start_date": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("The first date to count users on, formatted as `YYYY-MM-DD`. Defaults to %d days before `end_date`.", templateDAUsDefaultDays),
+				Optional:            true,
+				Computed:            true,
+			},
+			"end_date": schema.StringAttribute{
+				MarkdownDescription: "The date to count users until, exclusive, formatted as `YYYY-MM-DD`. Defaults to today, so today isn't counted.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"tz_hour_offset": schema.Int64Attribute{
+				MarkdownDescription: "The timezone offset from UTC, in hours, of the dates. Defaults to 0.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(-12, 14),
+				},
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "The number of active users in each interval, ordered by date.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"start_date": schema.StringAttribute{
+							MarkdownDescription: "The first date of the interval, formatted as `YYYY-MM-DD`.",
+							Computed:            true,
+						},
+						"end_date": schema.StringAttribute{
+							MarkdownDescription: "The date the interval ends before, formatted as `YYYY-MM-DD`.",
+							Computed:            true,
+						},
+						"active_users": schema.Int64Attribute{
+							MarkdownDescription: "The number of unique users of the template in the interval.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TemplateDAUsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *TemplateDAUsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TemplateDAUsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	if data.Interval.IsNull() {
+		data.Interval = types.StringValue(string(codersdk.InsightsReportIntervalDay))
+	}
+	if data.TZHourOffset.IsNull() {
+		data.TZHourOffset = types.Int64Value(0)
+	}
+	start, end, err := templateDAUsRange(data.StartDate.ValueString(), data.EndDate.ValueString(), int(data.TZHourOffset.ValueInt64()), time.Now())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Date Range", err.Error())
+		return
+	}
+	data.StartDate = types.StringValue(start.Format(time.DateOnly))
+	data.EndDate = types.StringValue(end.Format(time.DateOnly))
+
+	insights, err := client.TemplateInsights(ctx, codersdk.TemplateInsightsRequest{
+		StartTime:   start,
+		EndTime:     end,
+		TemplateIDs: []uuid.UUID{data.TemplateID.ValueUUID()},
+		Interval:    codersdk.InsightsReportInterval(data.Interval.ValueString()),
+		Sections:    []codersdk.TemplateInsightsSection{codersdk.TemplateInsightsSectionIntervalReports},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get template insights, got error: %s", err))
+		return
+	}
+
+	entries := make([]TemplateDAUsEntry, 0, len(insights.IntervalReports))
+	for _, report := range insights.IntervalReports {
+		entries = append(entries, TemplateDAUsEntry{
+			StartDate:   types.StringValue(report.StartTime.In(start.Location()).Format(time.DateOnly)),
+			EndDate:     types.StringValue(report.EndTime.In(start.Location()).Format(time.DateOnly)),
+			ActiveUsers: types.Int64Value(report.ActiveUsers),
+		})
+	}
+	data.Entries = entries
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// templateDAUsRange returns the start and end of the date range to report on,
+// at midnight in the timezone. Unset dates default to the days before today.
+func templateDAUsRange(startDate, endDate string, tzHourOffset int, now time.Time) (time.Time, time.Time, error) {
+	loc := time.FixedZone(fmt.Sprintf("UTC%+d", tzHourOffset), tzHourOffset*int(time.Hour/time.Second))
+	now = now.In(loc)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if endDate != "" {
+		var err error
+		end, err = time.ParseInLocation(time.DateOnly, endDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end_date must be formatted as YYYY-MM-DD: %w", err)
+		}
+	}
+	start := end.AddDate(0, 0, -templateDAUsDefaultDays)
+	if startDate != "" {
+		var err error
+		start, err = time.ParseInLocation(time.DateOnly, startDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start_date must be formatted as YYYY-MM-DD: %w", err)
+		}
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start_date %s must be before end_date %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+	return start, end, nil
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccTemplateDAUsDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "template_daus_data_acc", false)
+
+	cfg := testAccTemplateDAUsDataSourceConfig{
+		URL:       client.URL.String(),
+		Token:     client.SessionToken(),
+		Directory: "../../integration/template-test/example-template",
+		Interval:  PtrTo("week"),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.coderd_template_daus.test", "template_id", "coderd_template.test", "id"),
+					resource.TestCheckResourceAttr("data.coderd_template_daus.test", "interval", "week"),
+					resource.TestCheckResourceAttr("data.coderd_template_daus.test", "tz_hour_offset", "0"),
+					resource.TestCheckResourceAttrSet("data.coderd_template_daus.test", "start_date"),
+					resource.TestCheckResourceAttrSet("data.coderd_template_daus.test", "entries.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestTemplateDAUsRange(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 3, 10, 3, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name         string
+		startDate    string
+		endDate      string
+		tzHourOffset int
+		start        string
+		end          string
+		err          string
+	}{
+		{name: "Defaults", start: "2025-02-10T00:00:00Z", end: "2025-03-10T00:00:00Z"},
+		{name: "Offset", tzHourOffset: -5, start: "2025-02-09T00:00:00-05:00", end: "2025-03-09T00:00:00-05:00"},
+		{name: "EndDate", endDate: "2025-02-01", start: "2025-01-04T00:00:00Z", end: "2025-02-01T00:00:00Z"},
+		{name: "Both", startDate: "2025-01-01", endDate: "2025-01-08", start: "2025-01-01T00:00:00Z", end: "2025-01-08T00:00:00Z"},
+		{name: "InvalidDate", startDate: "01/01/2025", err: "YYYY-MM-DD"},
+		{name: "Reversed", startDate: "2025-01-08", endDate: "2025-01-01", err: "must be before"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			start, end, err := templateDAUsRange(c.startDate, c.endDate, c.tzHourOffset, now)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.start, start.Format(time.RFC3339))
+			require.Equal(t, c.end, end.Format(time.RFC3339))
+		})
+	}
+}
+
+type testAccTemplateDAUsDataSourceConfig struct {
+	URL   string
+	Token string
+
+	Directory string
+	Interval  *string
+}
+
+func (c testAccTemplateDAUsDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+resource "coderd_template" "test" {
+	name = "example-template"
+	versions = [{
+		directory = "{{.Directory}}"
+		active    = true
+	}]
+}
+
+data "coderd_template_daus" "test" {
+	template_id = coderd_template.test.id
+	interval    = {{orNull .Interval}}
+}
+`
+
+	funcMap := template.FuncMap{
+		"orNull": PrintOrNull,
+	}
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("templateDAUsDataSource").Funcs(funcMap).Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -3,15 +3,23 @@ package provider
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -21,6 +29,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -37,6 +46,8 @@ import (
 var _ resource.Resource = &TemplateResource{}
 var _ resource.ResourceWithImportState = &TemplateResource{}
 var _ resource.ResourceWithConfigValidators = &TemplateResource{}
+var _ resource.ResourceWithModifyPlan = &TemplateResource{}
+var _ resource.ResourceWithUpgradeState = &TemplateResource{}
 
 func NewTemplateResource() resource.Resource {
 	return &TemplateResource{}
@@ -56,8 +67,10 @@ type TemplateResourceModel struct {
 	Description                    types.String `tfsdk:"description"`
 	OrganizationID                 UUID         `tfsdk:"organization_id"`
 	Icon                           types.String `tfsdk:"icon"`
+	IconFile                       types.String `tfsdk:"icon_file"`
 	DefaultTTLMillis               types.Int64  `tfsdk:"default_ttl_ms"`
 	ActivityBumpMillis             types.Int64  `tfsdk:"activity_bump_ms"`
+	ActivityBump                   types.String `tfsdk:"activity_bump"`
 	AutostopRequirement            types.Object `tfsdk:"auto_stop_requirement"`
 	AutostartPermittedDaysOfWeek   types.Set    `tfsdk:"auto_start_permitted_days_of_week"`
 	AllowUserCancelWorkspaceJobs   types.Bool   `tfsdk:"allow_user_cancel_workspace_jobs"`
@@ -66,12 +79,27 @@ type TemplateResourceModel struct {
 	FailureTTLMillis               types.Int64  `tfsdk:"failure_ttl_ms"`
 	TimeTilDormantMillis           types.Int64  `tfsdk:"time_til_dormant_ms"`
 	TimeTilDormantAutoDeleteMillis types.Int64  `tfsdk:"time_til_dormant_autodelete_ms"`
+	FailureTTL                     types.String `tfsdk:"failure_ttl"`
+	TimeTilDormant                 types.String `tfsdk:"time_til_dormant"`
+	TimeTilDormantAutoDelete       types.String `tfsdk:"time_til_dormant_autodelete"`
 	RequireActiveVersion           types.Bool   `tfsdk:"require_active_version"`
 	DeprecationMessage             types.String `tfsdk:"deprecation_message"`
+	MaxPortShareLevel              types.String `tfsdk:"max_port_share_level"`
+	UseClassicParameterFlow        types.Bool   `tfsdk:"use_classic_parameter_flow"`
+	CORSBehavior                   types.String `tfsdk:"cors_behavior"`
 
 	// If null, we are not managing ACL via Terraform (such as for AGPL).
 	ACL      types.Object `tfsdk:"acl"`
 	Versions Versions     `tfsdk:"versions"`
+	// If null, the active version is set by the `active` attribute of the
+	// versions.
+	ActiveVersionID UUID `tfsdk:"active_version_id"`
+	// If null, old template versions are never archived.
+	VersionRetention types.Object `tfsdk:"version_retention"`
+	ValidateVersions types.Bool   `tfsdk:"validate_versions"`
+
+	DeletionProtection types.Bool `tfsdk:"deletion_protection"`
+	ForceDelete        types.Bool `tfsdk:"force_delete"`
 }
 
 // EqualTemplateMetadata returns true if two templates have identical metadata (excluding ACL).
@@ -91,7 +119,20 @@ func (m *TemplateResourceModel) EqualTemplateMetadata(other *TemplateResourceMod
 		m.FailureTTLMillis.Equal(other.FailureTTLMillis) &&
 		m.TimeTilDormantMillis.Equal(other.TimeTilDormantMillis) &&
 		m.TimeTilDormantAutoDeleteMillis.Equal(other.TimeTilDormantAutoDeleteMillis) &&
-		m.RequireActiveVersion.Equal(other.RequireActiveVersion)
+		m.RequireActiveVersion.Equal(other.RequireActiveVersion) &&
+		m.DeprecationMessage.Equal(other.DeprecationMessage) &&
+		m.MaxPortShareLevel.Equal(other.MaxPortShareLevel) &&
+		m.UseClassicParameterFlow.Equal(other.UseClassicParameterFlow) &&
+		m.CORSBehavior.Equal(other.CORSBehavior)
+}
+
+// replacesACL returns true if Terraform manages the entire ACL of the template.
+func (m *TemplateResourceModel) replacesACL() bool {
+	if m.ACL.IsNull() || m.ACL.IsUnknown() {
+		return false
+	}
+	mode, ok := m.ACL.Attributes()["mode"].(types.String)
+	return !ok || mode.ValueString() != aclModeMerge
 }
 
 func (m *TemplateResourceModel) CheckEntitlements(ctx context.Context, features map[codersdk.FeatureName]codersdk.Feature) (diags diag.Diagnostics) {
@@ -108,12 +149,17 @@ func (m *TemplateResourceModel) CheckEntitlements(ctx context.Context, features
 		m.TimeTilDormantMillis.ValueInt64() != 0 ||
 		len(m.AutostartPermittedDaysOfWeek.Elements()) != 7
 	requiresActiveVersion := m.RequireActiveVersion.ValueBool()
+	requiresDeprecation := m.DeprecationMessage.ValueString() != ""
+	requiresSharedPortControl := false
+	if !m.MaxPortShareLevel.IsNull() && !m.MaxPortShareLevel.IsUnknown() {
+		requiresSharedPortControl = m.MaxPortShareLevel.ValueString() != string(codersdk.WorkspaceAgentPortShareLevelPublic)
+	}
 	requiresACL := !m.ACL.IsNull()
-	if requiresScheduling || requiresActiveVersion || requiresACL {
+	if requiresScheduling || requiresActiveVersion || requiresDeprecation || requiresSharedPortControl || requiresACL {
 		if requiresScheduling && !features[codersdk.FeatureAdvancedTemplateScheduling].Enabled {
 			diags.AddError(
 				"Feature not enabled",
-				"Your license is not entitled to use advanced template scheduling, so you cannot modify any of the following fields from their defaults: auto_stop_requirement, auto_start_permitted_days_of_week, allow_user_auto_start, allow_user_auto_stop, failure_ttl_ms, time_til_dormant_ms, time_til_dormant_autodelete_ms.",
+				"Your license is not entitled to use advanced template scheduling, so you cannot modify any of the following fields from their defaults: auto_stop_requirement, auto_start_permitted_days_of_week, allow_user_auto_start, allow_user_auto_stop, failure_ttl(_ms), time_til_dormant(_ms), time_til_dormant_autodelete(_ms).",
 			)
 			return
 		}
@@ -124,6 +170,20 @@ func (m *TemplateResourceModel) CheckEntitlements(ctx context.Context, features
 			)
 			return
 		}
+		if requiresDeprecation && !features[codersdk.FeatureAccessControl].Enabled {
+			diags.AddError(
+				"Feature not enabled",
+				"Your license is not entitled to use access control, so you cannot set deprecation_message.",
+			)
+			return
+		}
+		if requiresSharedPortControl && !features[codersdk.FeatureControlSharedPorts].Enabled {
+			diags.AddError(
+				"Feature not enabled",
+				"Your license is not entitled to use port sharing control, so you cannot set max_port_share_level to anything other than \"public\".",
+			)
+			return
+		}
 		if requiresACL && !features[codersdk.FeatureTemplateRBAC].Enabled {
 			diags.AddError(
 				"Feature not enabled",
@@ -136,18 +196,33 @@ func (m *TemplateResourceModel) CheckEntitlements(ctx context.Context, features
 }
 
 type TemplateVersion struct {
-	ID                 UUID         `tfsdk:"id"`
-	Name               types.String `tfsdk:"name"`
-	Message            types.String `tfsdk:"message"`
-	Directory          types.String `tfsdk:"directory"`
-	DirectoryHash      types.String `tfsdk:"directory_hash"`
-	Active             types.Bool   `tfsdk:"active"`
-	TerraformVariables []Variable   `tfsdk:"tf_vars"`
-	ProvisionerTags    []Variable   `tfsdk:"provisioner_tags"`
+	ID                   UUID           `tfsdk:"id"`
+	Name                 types.String   `tfsdk:"name"`
+	NameTemplate         types.String   `tfsdk:"name_template"`
+	Message              types.String   `tfsdk:"message"`
+	Directory            types.String   `tfsdk:"directory"`
+	Git                  *GitSource     `tfsdk:"git"`
+	Archive              *ArchiveSource `tfsdk:"archive"`
+	Exclude              []string       `tfsdk:"exclude"`
+	DirectoryHash        types.String   `tfsdk:"directory_hash"`
+	NormalizeLineEndings types.Bool     `tfsdk:"normalize_line_endings"`
+	Active               types.Bool     `tfsdk:"active"`
+	TerraformVariables   []Variable     `tfsdk:"tf_vars"`
+	ProvisionerTags      []Variable     `tfsdk:"provisioner_tags"`
 }
 
 type Versions []TemplateVersion
 
+// ignoreRules returns the rules for excluding files in the template version's
+// source directory.
+func (v *TemplateVersion) ignoreRules(directory string) (ignoreRules, error) {
+	rules, err := loadIgnoreRules(directory, v.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exclude patterns: %w", err)
+	}
+	return rules, nil
+}
+
 func (v Versions) ByID(id UUID) *TemplateVersion {
 	for _, m := range v {
 		if m.ID.Equal(id) {
@@ -173,15 +248,38 @@ var variableNestedObject = schema.NestedAttributeObject{
 	},
 }
 
+var tfVarNestedObject = schema.NestedAttributeObject{
+	Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required: true,
+		},
+		"value": schema.StringAttribute{
+			Required:  true,
+			Sensitive: true,
+		},
+	},
+}
+
 type ACL struct {
 	UserPermissions  []Permission `tfsdk:"users"`
 	GroupPermissions []Permission `tfsdk:"groups"`
+	Mode             types.String `tfsdk:"mode"`
 }
 
+const (
+	// aclModeReplace manages the entire ACL, removing any users or groups that
+	// aren't in the configuration.
+	aclModeReplace = "replace"
+	// aclModeMerge only manages the users and groups in the configuration,
+	// leaving any others, including the Everyone group, untouched.
+	aclModeMerge = "merge"
+)
+
 // aclTypeAttr is the type schema for an instance of `ACL`.
 var aclTypeAttr = map[string]attr.Type{
 	"users":  permissionTypeAttr,
 	"groups": permissionTypeAttr,
+	"mode":   types.StringType,
 }
 
 type Permission struct {
@@ -197,10 +295,15 @@ var permissionAttribute = schema.SetNestedAttribute{
 	NestedObject: schema.NestedAttributeObject{
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Required: true,
+				MarkdownDescription: "The ID of the user or group. The ID of the Everyone group is the ID of the organization.",
+				Required:            true,
 			},
 			"role": schema.StringAttribute{
-				Required: true,
+				MarkdownDescription: "The role of the user or group. Valid values are `use` and `admin`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(codersdk.TemplateRoleUse), string(codersdk.TemplateRoleAdmin)),
+				},
 			},
 		},
 	},
@@ -224,15 +327,24 @@ var autostopRequirementTypeAttr = map[string]attr.Type{
 	"weeks":        basetypes.Int64Type{},
 }
 
+type VersionRetention struct {
+	KeepLast     types.Int64 `tfsdk:"keep_last"`
+	MaxAgeMillis types.Int64 `tfsdk:"max_age_ms"`
+}
+
 func (r *TemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_template"
 }
 
 func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: schemaVersion,
 		MarkdownDescription: "A Coder template.\n\nLogs from building template versions are streamed from the provisioner " +
-			"when the `TF_LOG` environment variable is `INFO` or higher.\n\n" +
-			"When importing, the ID supplied can be either a template UUID retrieved via the API or `<organization-name>/<template-name>`.",
+			"when the `TF_LOG` environment variable is `INFO` or higher. If building a template version fails, the last lines of its logs are included in the error.\n\n" +
+			"When importing, the ID supplied can be either a template UUID retrieved via the API or `<organization-name>/<template-name>`. " +
+			"The template's active version is imported into `versions`. As the source it was created from is unknown, " +
+			"the next apply creates a new version from the configured source, unless `reuse_template_versions` is enabled and the active version was created from the same source. " +
+			"With an Enterprise license, the ACL is imported as managed, in `replace` mode.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -259,6 +371,9 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 					stringvalidator.LengthBetween(1, 64),
 					stringvalidator.RegexMatches(displayNameRegex, "Template display names must be alphanumeric with spaces."),
 				},
+				PlanModifiers: []planmodifier.String{
+					NewDisplayNamePlanModifier(),
+				},
 			},
 			"description": schema.StringAttribute{
 				MarkdownDescription: "A description of the template.",
@@ -267,19 +382,31 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 				Default:             stringdefault.StaticString(""),
 			},
 			"organization_id": schema.StringAttribute{
-				MarkdownDescription: "The ID of the organization. Defaults to the provider's default organization",
-				CustomType:          UUIDType,
+				MarkdownDescription: "The ID of the organization. Defaults to the provider's default organization. " +
+					"Coder can't move templates between organizations, so changing the organization replaces the template, including unsetting it when the provider's default organization is configured and differs from the current one. " +
+					"The plan fails if workspaces have been created from the template, as it can't be deleted while they exist.",
+				CustomType: UUIDType,
+				Optional:   true,
+				Computed:   true,
+			},
+			"icon": schema.StringAttribute{
+				MarkdownDescription: "Relative path or external URL that specifes an icon to be displayed in the dashboard. Must be an https URL, an image data URL, a path to a built-in emoji or icon (e.g. `/emojis/1f680.png` or `/icon/code.svg`), or an emoji shortcode.",
 				Optional:            true,
 				Computed:            true,
+				Default:             stringdefault.StaticString(""),
+				Validators: []validator.String{
+					NewIconURLValidator(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplaceIfConfigured(),
+					NewIconFilePlanModifier("icon_file"),
 				},
 			},
-			"icon": schema.StringAttribute{
-				MarkdownDescription: "Relative path or external URL that specifes an icon to be displayed in the dashboard.",
+			"icon_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a local image file to use as the icon, which is embedded in `icon` as a data URL. Changes in the file contents will update the icon. The file must be no larger than 256 KiB. Conflicts with `icon`.",
 				Optional:            true,
-				Computed:            true,
-				Default:             stringdefault.StaticString(""),
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("icon")),
+				},
 			},
 			"default_ttl_ms": schema.Int64Attribute{
 				MarkdownDescription: "The default time-to-live for all workspaces created from this template, in milliseconds.",
@@ -288,10 +415,25 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 				Default:             int64default.StaticInt64(0),
 			},
 			"activity_bump_ms": schema.Int64Attribute{
-				MarkdownDescription: "The activity bump duration for all workspaces created from this template, in milliseconds. Defaults to one hour.",
+				MarkdownDescription: "The activity bump duration for all workspaces created from this template, in milliseconds. Defaults to one hour. Conflicts with `activity_bump`.",
 				Optional:            true,
 				Computed:            true,
 				Default:             int64default.StaticInt64(3600000),
+				PlanModifiers: []planmodifier.Int64{
+					NewMillisFromDurationPlanModifier("activity_bump"),
+				},
+			},
+			"activity_bump": schema.StringAttribute{
+				MarkdownDescription: "The amount of time a workspace's deadline is extended by when it is in use, for all workspaces created from this template, as a duration such as `2h` or `90m`. `0s` disables activity bumping. Defaults to one hour. Conflicts with `activity_bump_ms`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					NewDurationValidator(),
+					stringvalidator.ConflictsWith(path.MatchRoot("activity_bump_ms")),
+				},
+				PlanModifiers: []planmodifier.String{
+					NewDurationFromMillisPlanModifier("activity_bump_ms", 3600000),
+				},
 			},
 			"auto_stop_requirement": schema.SingleNestedAttribute{
 				MarkdownDescription: "(Enterprise) The auto-stop requirement for all workspaces created from this template.",
@@ -307,9 +449,10 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 						Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
 					},
 					"weeks": schema.Int64Attribute{
-						MarkdownDescription: "Weeks is the number of weeks between required restarts. Weeks are synced across all workspaces (and Coder deployments) using modulo math on a hardcoded epoch week of January 2nd, 2023 (the first Monday of 2023). Values of 0 or 1 indicate weekly restarts. Values of 2 indicate fortnightly restarts, etc.",
+						MarkdownDescription: "Weeks is the number of weeks between required restarts. Weeks are synced across all workspaces (and Coder deployments) using modulo math on a hardcoded epoch week of January 2nd, 2023 (the first Monday of 2023). Values of 0 or 1 indicate weekly restarts. Values of 2 indicate fortnightly restarts, etc. At most 16.",
 						Optional:            true,
 						Computed:            true,
+						Validators:          []validator.Int64{int64validator.Between(0, 16)},
 						Default:             int64default.StaticInt64(1),
 					},
 				},
@@ -327,62 +470,206 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 				Default:             setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("monday"), types.StringValue("tuesday"), types.StringValue("wednesday"), types.StringValue("thursday"), types.StringValue("friday"), types.StringValue("saturday"), types.StringValue("sunday")})),
 			},
 			"allow_user_cancel_workspace_jobs": schema.BoolAttribute{
-				MarkdownDescription: "Whether users can cancel in-progress workspace jobs using this template. Defaults to true.",
+				MarkdownDescription: "Whether users can cancel in-progress workspace jobs, such as builds, for workspaces created from this template. Defaults to true.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(true),
 			},
 			"allow_user_auto_start": schema.BoolAttribute{
-				MarkdownDescription: "(Enterprise) Whether users can auto-start workspaces created from this template. Defaults to true.",
+				MarkdownDescription: "(Enterprise) Whether users can set an auto-start schedule for workspaces created from this template. Defaults to true.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(true),
 			},
 			"allow_user_auto_stop": schema.BoolAttribute{
-				MarkdownDescription: "(Enterprise) Whether users can auto-start workspaces created from this template. Defaults to true.",
+				MarkdownDescription: "(Enterprise) Whether users can customize the auto-stop time of workspaces created from this template. If false, workspaces always use `default_ttl_ms`. Defaults to true.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(true),
 			},
 			"failure_ttl_ms": schema.Int64Attribute{
-				MarkdownDescription: "(Enterprise) The max lifetime before Coder stops all resources for failed workspaces created from this template, in milliseconds.",
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder stops all resources for failed workspaces created from this template, in milliseconds. Conflicts with `failure_ttl`.",
 				Optional:            true,
 				Computed:            true,
 				Default:             int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					NewMillisFromDurationPlanModifier("failure_ttl"),
+				},
+			},
+			"failure_ttl": schema.StringAttribute{
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder stops all resources for failed workspaces created from this template, as a duration such as `24h` or `1h30m`. Conflicts with `failure_ttl_ms`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					NewDurationValidator(),
+					stringvalidator.ConflictsWith(path.MatchRoot("failure_ttl_ms")),
+				},
+				PlanModifiers: []planmodifier.String{
+					NewDurationFromMillisPlanModifier("failure_ttl_ms", 0),
+				},
 			},
 			"time_til_dormant_ms": schema.Int64Attribute{
-				MarkdownDescription: "(Enterprise) The max lifetime before Coder locks inactive workspaces created from this template, in milliseconds.",
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder locks inactive workspaces created from this template, in milliseconds. Conflicts with `time_til_dormant`.",
 				Optional:            true,
 				Computed:            true,
 				Default:             int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					NewMillisFromDurationPlanModifier("time_til_dormant"),
+				},
+			},
+			"time_til_dormant": schema.StringAttribute{
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder locks inactive workspaces created from this template, as a duration such as `24h` or `1h30m`. Conflicts with `time_til_dormant_ms`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					NewDurationValidator(),
+					stringvalidator.ConflictsWith(path.MatchRoot("time_til_dormant_ms")),
+				},
+				PlanModifiers: []planmodifier.String{
+					NewDurationFromMillisPlanModifier("time_til_dormant_ms", 0),
+				},
 			},
 			"time_til_dormant_autodelete_ms": schema.Int64Attribute{
-				MarkdownDescription: "(Enterprise) The max lifetime before Coder permanently deletes dormant workspaces created from this template.",
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder permanently deletes dormant workspaces created from this template, in milliseconds. Conflicts with `time_til_dormant_autodelete`.",
 				Optional:            true,
 				Computed:            true,
 				Default:             int64default.StaticInt64(0),
+				PlanModifiers: []planmodifier.Int64{
+					NewMillisFromDurationPlanModifier("time_til_dormant_autodelete"),
+				},
+			},
+			"time_til_dormant_autodelete": schema.StringAttribute{
+				MarkdownDescription: "(Enterprise) The max lifetime before Coder permanently deletes dormant workspaces created from this template, as a duration such as `24h` or `1h30m`. Conflicts with `time_til_dormant_autodelete_ms`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					NewDurationValidator(),
+					stringvalidator.ConflictsWith(path.MatchRoot("time_til_dormant_autodelete_ms")),
+				},
+				PlanModifiers: []planmodifier.String{
+					NewDurationFromMillisPlanModifier("time_til_dormant_autodelete_ms", 0),
+				},
 			},
 			"require_active_version": schema.BoolAttribute{
-				MarkdownDescription: "(Enterprise) Whether workspaces must be created from the active version of this template. Defaults to false.",
+				MarkdownDescription: "(Enterprise) Whether workspaces must be created from the active version of this template, and are automatically updated to the active version when started. Template admins are exempt. Defaults to false.",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
 			},
+			"use_classic_parameter_flow": schema.BoolAttribute{
+				MarkdownDescription: "Whether workspaces created from this template use the classic parameter flow, rather than dynamic parameters. " +
+					"Requires a Coder deployment that supports dynamic parameters. Defaults to the deployment's default.",
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cors_behavior": schema.StringAttribute{
+				MarkdownDescription: "How CORS requests to workspace apps are handled for workspaces created from this template. " +
+					"`simple` applies Coder's own CORS headers, and `passthru` passes requests through to the workspace app, so browser-based apps can handle CORS themselves. " +
+					"Requires a Coder deployment that supports configuring CORS behavior. Defaults to the deployment's default.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("simple", "passthru"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"deprecation_message": schema.StringAttribute{
-				MarkdownDescription: "If set, the template will be marked as deprecated with the provided message and users will be blocked from creating new workspaces from it. Does nothing if set when the resource is created.",
+				MarkdownDescription: "(Enterprise) If set, the template will be marked as deprecated with the provided message and users will be blocked from creating new workspaces from it. Removing the message un-deprecates the template.",
 				Optional:            true,
 				Computed:            true,
 				Default:             stringdefault.StaticString(""),
 			},
+			"max_port_share_level": schema.StringAttribute{
+				MarkdownDescription: "(Enterprise) The maximum port share level for workspaces created from this template. Valid values are `owner`, `authenticated`, `organization` and `public`. `organization` requires Coder v2.21.0 or later. Defaults to `owner` on Enterprise deployments, and `public` otherwise.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("owner", "authenticated", "organization", "public"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"acl": schema.SingleNestedAttribute{
 				MarkdownDescription: "(Enterprise) Access control list for the template. If null, ACL policies will not be added, removed, or read by Terraform.",
 				Optional:            true,
 				Attributes: map[string]schema.Attribute{
 					"users":  permissionAttribute,
 					"groups": permissionAttribute,
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "How the ACL is managed. With `replace`, Terraform manages the entire ACL, and users or groups not in the configuration, including the Everyone group, are removed. " +
+							"With `merge`, only the users and groups in the configuration are managed, and any others are left untouched. Defaults to `replace`.",
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString(aclModeReplace),
+						Validators: []validator.String{
+							stringvalidator.OneOf(aclModeReplace, aclModeMerge),
+						},
+					},
+				},
+			},
+			"version_retention": schema.SingleNestedAttribute{
+				MarkdownDescription: "A policy for archiving old template versions that are not managed by Terraform, such as versions previously pushed by CI. " +
+					"The policy is applied after any new versions are pushed. Archived versions are hidden from the dashboard and cannot be used to create new workspaces. " +
+					"The active version, and versions in the `versions` list, are never archived. " +
+					"If both `keep_last` and `max_age_ms` are set, versions are only archived if they are outside both limits.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"keep_last": schema.Int64Attribute{
+						MarkdownDescription: "The number of most recently created template versions to retain.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+							int64validator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("max_age_ms")),
+						},
+					},
+					"max_age_ms": schema.Int64Attribute{
+						MarkdownDescription: "The age after which template versions are archived, in milliseconds.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
 				},
 			},
+			"active_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template version to make active. This may be any version of the template, such as a candidate version pushed by CI, so that promoting a version is decoupled from pushing it. " +
+					"If set, pushing new versions never changes the active version, and no version in `versions` may set `active`.",
+				CustomType: UUIDType,
+				Optional:   true,
+			},
+			"validate_versions": schema.BoolAttribute{
+				MarkdownDescription: "Whether to validate new and modified template versions before changing the template when applying. " +
+					"Each version is first imported by the provisioner as a template version that is archived once the import completes, and if any import fails, the apply fails without changing the template or its active version. " +
+					"This runs an extra provisioner job for each version. " +
+					"As Coder can only archive versions that belong to a template, versions aren't validated when creating or replacing the template. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether to refuse to delete the template while workspaces have been created from it, so destroying the template can't orphan them. " +
+					"Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "Whether to delete the workspaces created from the template before deleting the template, overriding `deletion_protection`. " +
+					"Each workspace is destroyed by a delete build, which must succeed. The value must be applied before it takes effect on destroy. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
 			"versions": schema.ListNestedAttribute{
+				MarkdownDescription: "The versions of the template. Versions are matched to previously pushed versions by their contents and name, so reordering or renaming versions doesn't push new versions. " +
+					"Existing versions are renamed first, and then new and modified versions are pushed in the order they appear. " +
+					"Exactly one version must be `active`, unless `active_version_id` is set.",
 				Required: true,
 				Validators: []validator.List{
 					listvalidator.SizeAtLeast(1),
@@ -403,6 +690,16 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 								stringvalidator.RegexMatches(templateVersionNameRegex, "Template version names must be alphanumeric with underscores and dots."),
 							},
 						},
+						"name_template": schema.StringAttribute{
+							MarkdownDescription: "A Go template used to generate the name of the template version when it is created, instead of a random name. " +
+								"`{{.Hash}}` is the first 7 characters of the directory hash, `{{.Date}}` is the UTC date, e.g. `2024-06-01`, and `{{.Timestamp}}` is the UTC time, e.g. `20240601T150405`. " +
+								"For example, `{{.Date}}-{{.Hash}}`. Conflicts with `name`.",
+							Optional: true,
+							Validators: []validator.String{
+								NewVersionNameTemplateValidator(),
+								stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("name")),
+							},
+						},
 						"message": schema.StringAttribute{
 							MarkdownDescription: "A message describing the changes in this version of the template. Messages longer than 72 characters will be truncated.",
 							Optional:            true,
@@ -410,33 +707,48 @@ func (r *TemplateResource) Schema(ctx context.Context, req resource.SchemaReques
 							Default:             stringdefault.StaticString(""),
 						},
 						"directory": schema.StringAttribute{
-							MarkdownDescription: "A path to the directory to create the template version from. Changes in the directory contents will trigger the creation of a new template version.",
-							Required:            true,
+							MarkdownDescription: "A path to the directory to create the template version from. Changes in the directory contents will trigger the creation of a new template version. Exactly one of `directory`, `git` or `archive` must be set.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(
+									path.MatchRelative().AtParent().AtName("git"),
+									path.MatchRelative().AtParent().AtName("archive"),
+								),
+							},
+						},
+						"git":     gitSourceAttribute,
+						"archive": archiveSourceAttribute,
+						"exclude": schema.ListAttribute{
+							MarkdownDescription: "Patterns of files to exclude from the template version, in addition to those in the `.terraformignore` file at the root of the template, if present. Patterns follow the `.gitignore` syntax. `.git` and `.terraform` directories are always excluded, unless re-included with a negated pattern.",
+							Optional:            true,
+							ElementType:         types.StringType,
 						},
 						"directory_hash": schema.StringAttribute{
-							Computed: true,
+							MarkdownDescription: "A hash of the relative paths and contents of the files in the template version. File modes and timestamps don't affect the hash, so a new version is only pushed when the files change.",
+							Computed:            true,
+						},
+						"normalize_line_endings": schema.BoolAttribute{
+							MarkdownDescription: "Whether to treat CRLF and LF line endings as equivalent when detecting changes to the files, so checkouts on different operating systems don't push new versions. The files are uploaded unchanged. Defaults to false.",
+							Optional:            true,
 						},
 						"active": schema.BoolAttribute{
-							MarkdownDescription: "Whether this version is the active version of the template. Only one version can be active at a time.",
+							MarkdownDescription: "Whether this version is the active version of the template. Only one version can be active at a time. Can't be set if `active_version_id` is set.",
 							Computed:            true,
 							Optional:            true,
 							Default:             booldefault.StaticBool(false),
 						},
 						"tf_vars": schema.SetNestedAttribute{
-							MarkdownDescription: "Terraform variables for the template version.",
+							MarkdownDescription: "Terraform variables for the template version. Values are treated as sensitive. Variables set here take precedence over those in `.tfvars` files in the template directory.",
 							Optional:            true,
-							NestedObject:        variableNestedObject,
+							NestedObject:        tfVarNestedObject,
 						},
 						"provisioner_tags": schema.SetNestedAttribute{
-							MarkdownDescription: "Provisioner tags for the template version.",
+							MarkdownDescription: "Provisioner tags for the template version. Merged with the provider's `default_provisioner_tags`, taking precedence over them.",
 							Optional:            true,
 							NestedObject:        variableNestedObject,
 						},
 					},
 				},
-				PlanModifiers: []planmodifier.List{
-					NewVersionsPlanModifier(),
-				},
 			},
 		},
 	}
@@ -489,8 +801,11 @@ func (r *TemplateResource) Create(ctx context.Context, req resource.CreateReques
 	var templateResp codersdk.Template
 	for idx, version := range data.Versions {
 		newVersionRequest := newVersionRequest{
-			Version:        &version,
-			OrganizationID: orgID,
+			Version:                &version,
+			OrganizationID:         orgID,
+			JobTimeout:             r.data.JobTimeout,
+			DefaultProvisionerTags: r.data.DefaultProvisionerTags,
+			RecordFingerprint:      r.data.ReuseTemplateVersions,
 		}
 		if idx > 0 {
 			newVersionRequest.TemplateID = &templateResp.ID
@@ -506,7 +821,17 @@ func (r *TemplateResource) Create(ctx context.Context, req resource.CreateReques
 			if resp.Diagnostics.HasError() {
 				return
 			}
-			templateResp, err = client.CreateTemplate(ctx, orgID, *createReq)
+			templateResp, err = createIdempotently(ctx, "template", func() (codersdk.Template, error) {
+				return client.CreateTemplate(ctx, orgID, *createReq)
+			}, func(time.Time, error) (codersdk.Template, bool, error) {
+				template, err := client.TemplateByName(ctx, orgID, createReq.Name)
+				if isNotFound(err) {
+					return template, false, nil
+				}
+				// The template is ours if its active version is the one
+				// we just created.
+				return template, err == nil && template.ActiveVersionID == versionResp.ID, err
+			})
 			if err != nil {
 				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to create template: %s", err))
 				return
@@ -514,6 +839,35 @@ func (r *TemplateResource) Create(ctx context.Context, req resource.CreateReques
 			tflog.Info(ctx, "successfully created template", map[string]any{
 				"id": templateResp.ID,
 			})
+			// Delete the template if a later step fails, so the next apply
+			// creates it again, or save it if it can't be deleted.
+			defer func() {
+				if resp.Diagnostics.HasError() && !rollbackCreate(ctx, resp, "template", func(ctx context.Context) error {
+					return client.DeleteTemplate(ctx, templateResp.ID)
+				}) {
+					data.savePartialState(ctx, templateResp.ID, resp)
+				}
+			}()
+
+			// The deprecation message, max port share level, parameter flow
+			// and CORS behavior can't be set on creation, so they're set
+			// afterwards.
+			portShareLevelChanged := !data.MaxPortShareLevel.IsUnknown() &&
+				data.MaxPortShareLevel.ValueString() != string(templateResp.MaxPortShareLevel)
+			extraFields := data.extraUpdateFields()
+			if data.DeprecationMessage.ValueString() != "" || portShareLevelChanged || len(extraFields) > 0 {
+				tflog.Info(ctx, "updating template metadata not supported on creation")
+				updateReq := data.toUpdateRequest(ctx, &resp.Diagnostics)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				templateResp, err = updateTemplateMeta(ctx, client, templateResp.ID, *updateReq, extraFields)
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to update template metadata: %s", err))
+					return
+				}
+				tflog.Info(ctx, "successfully updated template metadata")
+			}
 
 			// Read the response into the state to set computed fields
 			diag := data.readResponse(ctx, &templateResp)
@@ -521,6 +875,11 @@ func (r *TemplateResource) Create(ctx context.Context, req resource.CreateReques
 				resp.Diagnostics.Append(diag...)
 				return
 			}
+			err = data.readExtraSettings(ctx, client, templateResp.ID)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", err.Error())
+				return
+			}
 
 			if !data.ACL.IsNull() {
 				tflog.Info(ctx, "updating template ACL")
@@ -550,8 +909,20 @@ func (r *TemplateResource) Create(ctx context.Context, req resource.CreateReques
 		data.Versions[idx].Name = types.StringValue(versionResp.Name)
 	}
 	data.ID = UUIDValue(templateResp.ID)
+	if !data.ActiveVersionID.IsNull() {
+		err := markActive(ctx, client, templateResp.ID, data.ActiveVersionID.ValueUUID())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", err.Error())
+			return
+		}
+	}
 	data.DisplayName = types.StringValue(templateResp.DisplayName)
 
+	resp.Diagnostics.Append(data.applyVersionRetention(ctx, client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.Diagnostics.Append(data.Versions.setPrivateState(ctx, resp.Private)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -574,7 +945,25 @@ func (r *TemplateResource) Read(ctx context.Context, req resource.ReadRequest, r
 
 	templateID := data.ID.ValueUUID()
 
+	// Not set when importing
+	if data.ValidateVersions.IsNull() {
+		data.ValidateVersions = types.BoolValue(false)
+	}
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(true)
+	}
+	if data.ForceDelete.IsNull() {
+		data.ForceDelete = types.BoolValue(false)
+	}
+
 	template, err := client.Template(ctx, templateID)
+	if isNotFound(err) {
+		tflog.Warn(ctx, "template not found, removing from state", map[string]any{
+			"id": templateID.String(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template: %s", err))
 		return
@@ -585,6 +974,16 @@ func (r *TemplateResource) Read(ctx context.Context, req resource.ReadRequest, r
 		resp.Diagnostics.Append(diag...)
 		return
 	}
+	if !data.ActiveVersionID.IsNull() {
+		data.ActiveVersionID = UUIDValue(template.ActiveVersionID)
+	}
+	data.UseClassicParameterFlow = types.BoolNull()
+	data.CORSBehavior = types.StringNull()
+	err = data.readExtraSettings(ctx, client, templateID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", err.Error())
+		return
+	}
 
 	if !data.ACL.IsNull() {
 		tflog.Info(ctx, "reading template ACL")
@@ -593,7 +992,16 @@ func (r *TemplateResource) Read(ctx context.Context, req resource.ReadRequest, r
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template ACL: %s", err))
 			return
 		}
-		tfACL := convertResponseToACL(acl)
+		var prevACL ACL
+		resp.Diagnostics.Append(data.ACL.As(ctx, &prevACL, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if prevACL.Mode.ValueString() == aclModeMerge {
+			// Only detect drift in the users and groups we manage
+			acl = filterTemplateACL(acl, prevACL)
+		}
+		tfACL := convertResponseToACL(acl, prevACL.Mode)
 		aclObj, diag := types.ObjectValueFrom(ctx, aclTypeAttr, tfACL)
 		if diag.HasError() {
 			resp.Diagnostics.Append(diag...)
@@ -603,17 +1011,34 @@ func (r *TemplateResource) Read(ctx context.Context, req resource.ReadRequest, r
 		tflog.Info(ctx, "read template ACL")
 	}
 
+	// List the versions once, rather than getting each of them.
+	versions, err := listTemplateVersions(ctx, client, templateID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to list template versions: %s", err))
+		return
+	}
 	for idx, version := range data.Versions {
+		// Versions that weren't pushed when the template was partially
+		// created are pushed by the next apply.
+		if version.ID.IsNull() {
+			continue
+		}
 		versionID := version.ID.ValueUUID()
-		versionResp, err := client.TemplateVersion(ctx, versionID)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template version: %s", err))
-			return
+		versionResp, ok := versions[versionID]
+		if !ok {
+			versionResp, err = client.TemplateVersion(ctx, versionID)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template version: %s", err))
+				return
+			}
 		}
 		data.Versions[idx].Name = types.StringValue(versionResp.Name)
-		data.Versions[idx].Message = types.StringValue(versionResp.Message)
+		message, _ := splitVersionMessage(versionResp.Message)
+		data.Versions[idx].Message = types.StringValue(message)
+		// When the active version is pinned by ID, `active` is never set on
+		// the versions, even if the pinned version is one of them.
 		active := false
-		if versionResp.ID == template.ActiveVersionID {
+		if versionResp.ID == template.ActiveVersionID && data.ActiveVersionID.IsNull() {
 			active = true
 		}
 		data.Versions[idx].Active = types.BoolValue(active)
@@ -659,15 +1084,22 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 
 	client := r.data.Client
 
+	if newState.ValidateVersions.ValueBool() {
+		resp.Diagnostics.Append(r.validateVersions(ctx, &newState, orgID, templateID)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	templateMetadataChanged := !newState.EqualTemplateMetadata(&curState)
 	// This is required, as the API will reject no-diff updates.
 	if templateMetadataChanged {
 		tflog.Info(ctx, "change in template metadata detected, updating.")
-		updateReq := newState.toUpdateRequest(ctx, resp)
+		updateReq := newState.toUpdateRequest(ctx, &resp.Diagnostics)
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		_, err := client.UpdateTemplateMeta(ctx, templateID, *updateReq)
+		_, err := updateTemplateMeta(ctx, client, templateID, *updateReq, newState.extraUpdateFields())
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to update template metadata: %s", err))
 			return
@@ -678,7 +1110,7 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 
 	// Since the everyone group always gets deleted by `DisableEveryoneGroupAccess`, we need to run this even if there
 	// were no ACL changes but the template metadata was updated.
-	if !newState.ACL.IsNull() && (!curState.ACL.Equal(newState.ACL) || templateMetadataChanged) {
+	if !newState.ACL.IsNull() && (!curState.ACL.Equal(newState.ACL) || (templateMetadataChanged && newState.replacesACL())) {
 		var acl ACL
 		resp.Diagnostics.Append(newState.ACL.As(ctx, &acl, basetypes.ObjectAsOptions{})...)
 		if resp.Diagnostics.HasError() {
@@ -689,6 +1121,17 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template ACL: %s", err))
 			return
 		}
+		if acl.Mode.ValueString() == aclModeMerge {
+			// Only remove the users and groups we previously managed
+			var prevACL ACL
+			if !curState.ACL.IsNull() {
+				resp.Diagnostics.Append(curState.ACL.As(ctx, &prevACL, basetypes.ObjectAsOptions{})...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+			}
+			curACL = filterTemplateACL(curACL, prevACL)
+		}
 
 		err = client.UpdateTemplateACL(ctx, templateID, convertACLToRequest(curACL, acl))
 		if err != nil {
@@ -698,25 +1141,48 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 		tflog.Info(ctx, "successfully updated template ACL")
 	}
 
+	// Existing versions are renamed before new versions are pushed, so new
+	// versions can reuse their names.
+	resp.Diagnostics.Append(updateVersionMetadata(ctx, client, curState.Versions, newState.Versions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var latest *codersdk.TemplateVersion
 	for idx := range newState.Versions {
 		if newState.Versions[idx].ID.IsUnknown() {
 			tflog.Info(ctx, "discovered a new or modified template version")
-			uploadResp, err := newVersion(ctx, client, newVersionRequest{
-				Version:        &newState.Versions[idx],
-				OrganizationID: orgID,
-				TemplateID:     &templateID,
-			})
-			if err != nil {
-				resp.Diagnostics.AddError("Client Error", err.Error())
-				return
+			if r.data.ReuseTemplateVersions && latest == nil {
+				var err error
+				latest, err = latestTemplateVersion(ctx, client, templateID)
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to list template versions: %s", err))
+					return
+				}
 			}
-			versionResp, err := client.TemplateVersion(ctx, uploadResp.ID)
-			if err != nil {
-				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template version: %s", err))
-				return
+			if latest != nil && newState.Versions.ByID(UUIDValue(latest.ID)) == nil &&
+				matchesRemoteVersion(*latest, &newState.Versions[idx], r.data.DefaultProvisionerTags) {
+				tflog.Info(ctx, "reusing the latest template version, which has the same contents", map[string]any{
+					"id": latest.ID.String(),
+				})
+				newState.Versions[idx].ID = UUIDValue(latest.ID)
+				newState.Versions[idx].Name = types.StringValue(latest.Name)
+			} else {
+				uploadResp, err := newVersion(ctx, client, newVersionRequest{
+					Version:                &newState.Versions[idx],
+					OrganizationID:         orgID,
+					TemplateID:             &templateID,
+					JobTimeout:             r.data.JobTimeout,
+					DefaultProvisionerTags: r.data.DefaultProvisionerTags,
+					RecordFingerprint:      r.data.ReuseTemplateVersions,
+				})
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", err.Error())
+					return
+				}
+				newState.Versions[idx].ID = UUIDValue(uploadResp.ID)
+				newState.Versions[idx].Name = types.StringValue(uploadResp.Name)
 			}
-			newState.Versions[idx].ID = UUIDValue(versionResp.ID)
-			newState.Versions[idx].Name = types.StringValue(versionResp.Name)
 			if newState.Versions[idx].Active.ValueBool() {
 				err := markActive(ctx, client, templateID, newState.Versions[idx].ID.ValueUUID())
 				if err != nil {
@@ -733,16 +1199,6 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Public/Private State Mismatch: failed to find template version with ID %s", newState.Versions[idx].ID))
 				return
 			}
-			if !curVersion.Name.Equal(newState.Versions[idx].Name) {
-				_, err := client.UpdateTemplateVersion(ctx, newState.Versions[idx].ID.ValueUUID(), codersdk.PatchTemplateVersionRequest{
-					Name:    newState.Versions[idx].Name.ValueString(),
-					Message: newState.Versions[idx].Message.ValueStringPointer(),
-				})
-				if err != nil {
-					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to update template version metadata: %s", err))
-					return
-				}
-			}
 			if newState.Versions[idx].Active.ValueBool() && !curVersion.Active.ValueBool() {
 				err := markActive(ctx, client, templateID, newState.Versions[idx].ID.ValueUUID())
 				if err != nil {
@@ -753,6 +1209,19 @@ func (r *TemplateResource) Update(ctx context.Context, req resource.UpdateReques
 		}
 	}
 
+	if !newState.ActiveVersionID.IsNull() && !newState.ActiveVersionID.Equal(curState.ActiveVersionID) {
+		err := markActive(ctx, client, templateID, newState.ActiveVersionID.ValueUUID())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(newState.applyVersionRetention(ctx, client)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.Diagnostics.Append(newState.Versions.setPrivateState(ctx, resp.Private)...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -776,6 +1245,35 @@ func (r *TemplateResource) Delete(ctx context.Context, req resource.DeleteReques
 
 	templateID := data.ID.ValueUUID()
 
+	if data.ForceDelete.ValueBool() || data.DeletionProtection.ValueBool() {
+		workspaces, err := templateWorkspaces(ctx, client, templateID, data.Name.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to list workspaces of template: %s", err))
+			return
+		}
+		if data.ForceDelete.ValueBool() {
+			for _, ws := range workspaces {
+				err = deleteWorkspace(ctx, client, ws, false, r.data.JobTimeout)
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to delete workspace %s/%s: %s", ws.OwnerName, ws.Name, err))
+					return
+				}
+			}
+		} else if len(workspaces) > 0 {
+			running := 0
+			for _, ws := range workspaces {
+				if ws.LatestBuild.Status == codersdk.WorkspaceStatusRunning {
+					running++
+				}
+			}
+			resp.Diagnostics.AddError("Template Has Workspaces",
+				fmt.Sprintf("Template %q can't be deleted, as %d workspace(s) (%d running) were created from it. "+
+					"Migrate or delete the workspaces, or set force_delete = true and apply before destroying to delete them.",
+					data.Name.ValueString(), len(workspaces), running))
+			return
+		}
+	}
+
 	tflog.Info(ctx, "deleting template")
 	err := client.DeleteTemplate(ctx, templateID)
 	if err != nil {
@@ -785,28 +1283,70 @@ func (r *TemplateResource) Delete(ctx context.Context, req resource.DeleteReques
 }
 
 func (r *TemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	client := r.data.Client
+	var template codersdk.Template
 	idParts := strings.Split(req.ID, "/")
 	if len(idParts) == 1 {
-		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
-		return
+		templateID, err := uuid.Parse(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to parse import template ID as UUID, got error: %s", err))
+			return
+		}
+		template, err = client.Template(ctx, templateID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template: %s", err))
+			return
+		}
 	} else if len(idParts) == 2 {
-		client := r.data.Client
-		org, err := client.OrganizationByName(ctx, idParts[0])
+		org, err := r.data.organizationByName(ctx, idParts[0])
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get organization with name %s: %s", idParts[0], err))
 			return
 		}
-		template, err := client.TemplateByName(ctx, org.ID, idParts[1])
+		template, err = client.TemplateByName(ctx, org.ID, idParts[1])
 		if err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template with name %s: %s", idParts[1], err))
 			return
 		}
-		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), template.ID.String())...)
-		return
 	} else {
 		resp.Diagnostics.AddError("Client Error", "Invalid import ID format, expected a single UUID or `<organization-name>/<template-name>`")
 		return
 	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), template.ID.String())...)
+
+	// Import the active version, so the next push is planned as a new
+	// version of the template. The source of the version is unknown, so
+	// its directory hash is left empty, which never matches a source.
+	version, err := client.TemplateVersion(ctx, template.ActiveVersionID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get active template version: %s", err))
+		return
+	}
+	message, _ := splitVersionMessage(version.Message)
+	versions := Versions{{
+		ID:                   UUIDValue(version.ID),
+		Name:                 types.StringValue(version.Name),
+		Message:              types.StringValue(message),
+		DirectoryHash:        types.StringValue(""),
+		NormalizeLineEndings: types.BoolValue(false),
+		Active:               types.BoolValue(true),
+	}}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("versions"), versions)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Import the ACL as managed, so configuration generated from the state
+	// is complete.
+	if r.data.Features[codersdk.FeatureTemplateRBAC].Enabled {
+		acl, err := client.TemplateACL(ctx, template.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to get template ACL: %s", err))
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("acl"), convertResponseToACL(acl, types.StringValue(aclModeReplace)))...)
+	}
+	// The remaining attributes are populated by Read.
 }
 
 // ConfigValidators implements resource.ResourceWithConfigValidators.
@@ -814,6 +1354,152 @@ func (r *TemplateResource) ConfigValidators(context.Context) []resource.ConfigVa
 	return []resource.ConfigValidator{}
 }
 
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *TemplateResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(ctx, r)
+}
+
+// ModifyPlan implements resource.ResourceWithModifyPlan.
+func (r *TemplateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when destroying.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	r.planVersions(ctx, req, resp)
+	// Nothing to validate if the provider isn't configured yet.
+	if resp.Diagnostics.HasError() || r.data == nil {
+		return
+	}
+	planOrganizationID(ctx, r.data, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var data TemplateResourceModel
+	// The plan may contain unknown values that can't be read into the model,
+	// in which case there's nothing to check yet.
+	if diags := resp.Plan.Get(ctx, &data); diags.HasError() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state TemplateResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(r.checkOrganizationReplace(ctx, &state, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.OrganizationID.IsUnknown() {
+		resp.Diagnostics.Append(r.checkNameConflict(ctx, req, data.OrganizationID.ValueUUID())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if r.data.ValidateMembers && !data.ACL.IsNull() && !data.ACL.IsUnknown() {
+		var acl ACL
+		resp.Diagnostics.Append(data.ACL.As(ctx, &acl, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(checkUsersExist(ctx, r.data.Client, path.Root("acl").AtName("users"), permissionIDs(acl.UserPermissions))...)
+		resp.Diagnostics.Append(checkGroupsExist(ctx, r.data.Client, path.Root("acl").AtName("groups"), permissionIDs(acl.GroupPermissions))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+}
+
+// validateVersions imports each new or modified version as a dry run, before
+// the template is changed, so a version that fails to import doesn't leave the
+// template partially updated. The versions created are archived, so they
+// aren't listed alongside the versions that are pushed.
+func (r *TemplateResource) validateVersions(ctx context.Context, data *TemplateResourceModel, orgID, templateID uuid.UUID) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for idx, version := range data.Versions {
+		// Only new or modified versions are pushed.
+		if !version.ID.IsUnknown() {
+			continue
+		}
+		dryRun := version
+		// Let Coder generate a name, so it can't conflict with the version
+		// that's pushed.
+		dryRun.Name = types.StringNull()
+		dryRun.NameTemplate = types.StringNull()
+		dryRun.Message = types.StringValue("Validated by Terraform")
+		tflog.Info(ctx, "validating template version", map[string]any{
+			"name": version.Name.ValueString(),
+		})
+		validated, err := newVersion(ctx, r.data.Client, newVersionRequest{
+			Version:                &dryRun,
+			OrganizationID:         orgID,
+			TemplateID:             &templateID,
+			JobTimeout:             r.data.JobTimeout,
+			DefaultProvisionerTags: r.data.DefaultProvisionerTags,
+		})
+		if validated != nil {
+			// The context may have been cancelled.
+			archiveErr := r.data.Client.SetArchiveTemplateVersion(context.WithoutCancel(ctx), validated.ID, true)
+			if archiveErr != nil {
+				diags.AddWarning("Client Warning", fmt.Sprintf("Failed to archive template version %s created for validation: %s", validated.ID, archiveErr))
+			}
+		}
+		if err != nil {
+			diags.AddAttributeError(path.Root("versions").AtListIndex(idx), "Template Version Validation Failed", err.Error())
+			continue
+		}
+		tflog.Info(ctx, "successfully validated template version")
+	}
+	return diags
+}
+
+// checkNameConflict returns an error if the template is being created or
+// renamed, and another template in the organization already has the name.
+func (r *TemplateResource) checkNameConflict(ctx context.Context, req resource.ModifyPlanRequest, orgID uuid.UUID) diag.Diagnostics {
+	name, changed, diags := plannedChange[types.String](ctx, req, path.Root("name"))
+	if !changed || diags.HasError() {
+		return diags
+	}
+	_, err := r.data.Client.TemplateByName(ctx, orgID, name)
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		diags.AddError("Client Error", fmt.Sprintf("Failed to check for template %q: %s", name, err))
+	default:
+		diags.AddAttributeError(path.Root("name"), "Template Name Conflict",
+			fmt.Sprintf("A template named %q already exists in the organization. Choose another name, or import the existing template with `terraform import`.", name))
+	}
+	return diags
+}
+
+// checkOrganizationReplace prevents replacing a template with workspaces when
+// its organization changes. Coder can't move templates between organizations,
+// so the template would have to be deleted, and templates can't be deleted
+// while workspaces use them.
+func (r *TemplateResource) checkOrganizationReplace(ctx context.Context, state, plan *TemplateResourceModel) (diags diag.Diagnostics) {
+	if plan.OrganizationID.IsUnknown() || plan.OrganizationID.IsNull() || plan.OrganizationID.Equal(state.OrganizationID) {
+		return diags
+	}
+	workspaces, err := templateWorkspaces(ctx, r.data.Client, state.ID.ValueUUID(), state.Name.ValueString())
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Failed to list workspaces of template: %s", err))
+		return diags
+	}
+	count := len(workspaces)
+	if count == 0 {
+		return diags
+	}
+	diags.AddAttributeError(path.Root("organization_id"), "Cannot Replace Template",
+		fmt.Sprintf("Coder can't move templates between organizations, so changing organization_id deletes template %q and creates a new one, "+
+			"but the template can't be deleted while %d workspace(s) use it. "+
+			"Keep the current organization_id, or delete the workspaces before changing it.", state.Name.ValueString(), count))
+	return diags
+}
+
 type activeVersionValidator struct{}
 
 func NewActiveVersionValidator() validator.List {
@@ -827,7 +1513,7 @@ func (a *activeVersionValidator) Description(ctx context.Context) string {
 
 // MarkdownDescription implements validator.List.
 func (a *activeVersionValidator) MarkdownDescription(context.Context) string {
-	return "Validate that exactly one template version has active set to true."
+	return "Validate that exactly one template version has active set to true, unless active_version_id is set."
 }
 
 // ValidateList implements validator.List.
@@ -838,10 +1524,21 @@ func (a *activeVersionValidator) ValidateList(ctx context.Context, req validator
 		return
 	}
 
+	var activeVersionID UUID
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("active_version_id"), &activeVersionID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	pinned := !activeVersionID.IsNull()
+
 	// Check if only one item in Version has active set to true
 	active := false
 	for _, version := range data {
 		if version.Active.ValueBool() {
+			if pinned {
+				resp.Diagnostics.AddError("Client Error", "Template versions can't be marked active when active_version_id is set.")
+				return
+			}
 			if active {
 				resp.Diagnostics.AddError("Client Error", "Only one template version can be active at a time.")
 				return
@@ -849,8 +1546,8 @@ func (a *activeVersionValidator) ValidateList(ctx context.Context, req validator
 			active = true
 		}
 	}
-	if !active {
-		resp.Diagnostics.AddError("Client Error", "At least one template version must be active.")
+	if !active && !pinned {
+		resp.Diagnostics.AddError("Client Error", "At least one template version must be active, or active_version_id must be set.")
 	}
 
 	// Check all versions have unique names
@@ -869,40 +1566,29 @@ func (a *activeVersionValidator) ValidateList(ctx context.Context, req validator
 
 var _ validator.List = &activeVersionValidator{}
 
-type versionsPlanModifier struct{}
-
-// Description implements planmodifier.Object.
-func (d *versionsPlanModifier) Description(ctx context.Context) string {
-	return d.MarkdownDescription(ctx)
-}
-
-// MarkdownDescription implements planmodifier.Object.
-func (d *versionsPlanModifier) MarkdownDescription(context.Context) string {
-	return "Compute the hash of a directory."
-}
-
-// PlanModifyObject implements planmodifier.List.
-func (d *versionsPlanModifier) PlanModifyList(ctx context.Context, req planmodifier.ListRequest, resp *planmodifier.ListResponse) {
+// planVersions computes the directory hashes of the planned versions, and
+// plans the IDs of the versions that are unchanged. It's done when modifying
+// the plan of the resource, rather than by a plan modifier on the versions,
+// as plan modifiers are created with the schema, before the resource is
+// configured, so couldn't use the provider's hash cache.
+func (r *TemplateResource) planVersions(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var planValue, configValue types.List
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("versions"), &planValue)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("versions"), &configValue)...)
+	if resp.Diagnostics.HasError() || planValue.IsUnknown() || configValue.IsUnknown() {
+		return
+	}
 	var planVersions Versions
-	resp.Diagnostics.Append(req.PlanValue.ElementsAs(ctx, &planVersions, false)...)
+	resp.Diagnostics.Append(planValue.ElementsAs(ctx, &planVersions, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	var configVersions Versions
-	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &configVersions, false)...)
+	resp.Diagnostics.Append(configValue.ElementsAs(ctx, &configVersions, false)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	for i := range planVersions {
-		hash, err := computeDirectoryHash(planVersions[i].Directory.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to compute directory hash: %s", err))
-			return
-		}
-		planVersions[i].DirectoryHash = types.StringValue(hash)
-	}
-
 	var lv LastVersionsByHash
 	lvBytes, diag := req.Private.GetKey(ctx, LastVersionsKey)
 	if diag.HasError() {
@@ -920,29 +1606,81 @@ func (d *versionsPlanModifier) PlanModifyList(ctx context.Context, req planmodif
 		}
 	}
 
-	planVersions.reconcileVersionIDs(lv, configVersions)
+	var cache hashCache
+	if r.data != nil {
+		cache.dir = r.data.HashCacheDir
+	}
+	for i := range planVersions {
+		if planVersions[i].Git != nil {
+			// Resolve the ref again, rather than checking out the commit
+			// it resolved to previously.
+			planVersions[i].Git.Commit = types.StringUnknown()
+		}
+		if planVersions[i].sourceUnknown() {
+			planVersions[i].DirectoryHash = types.StringUnknown()
+			continue
+		}
+		dir, cleanup, err := planVersions[i].sourceDirectory(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to retrieve template version source: %s", err))
+			return
+		}
+		rules, err := planVersions[i].ignoreRules(dir)
+		if err != nil {
+			cleanup()
+			resp.Diagnostics.AddError("Client Error", err.Error())
+			return
+		}
+		hash, err := cache.directoryHash(dir, rules, planVersions[i].NormalizeLineEndings.ValueBool())
+		if err == nil {
+			err = migrateLegacyHash(lv, hash, func() (string, error) {
+				return computeLegacyDirectoryHash(dir, rules)
+			})
+		}
+		cleanup()
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to compute directory hash: %s", err))
+			return
+		}
+		planVersions[i].DirectoryHash = types.StringValue(hash)
+	}
 
-	resp.PlanValue, resp.Diagnostics = types.ListValueFrom(ctx, req.PlanValue.ElementType(ctx), planVersions)
-}
+	planVersions.reconcileVersionIDs(lv, configVersions)
 
-func NewVersionsPlanModifier() planmodifier.List {
-	return &versionsPlanModifier{}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("versions"), planVersions)...)
 }
 
-var _ planmodifier.List = &versionsPlanModifier{}
-
 var weekValidator = setvalidator.ValueStringsAre(
 	stringvalidator.OneOf("monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"),
 )
 
-func uploadDirectory(ctx context.Context, client *codersdk.Client, logger slog.Logger, directory string) (*codersdk.UploadResponse, error) {
+func uploadDirectory(ctx context.Context, client *codersdk.Client, logger slog.Logger, directory string, rules ignoreRules) (*codersdk.UploadResponse, error) {
+	// Check the size before uploading anything, as the archive is streamed
+	// to the deployment as it's built.
+	files, size, err := directorySize(directory, rules)
+	if err != nil {
+		return nil, err
+	}
+	if size > provisionersdk.TemplateArchiveLimit {
+		return nil, fmt.Errorf("the %d files in the directory total %d bytes, exceeding the template size limit of %d bytes; exclude files with %s or the version's exclude patterns",
+			files, size, provisionersdk.TemplateArchiveLimit, terraformIgnoreFile)
+	}
+	tflog.Info(ctx, "archiving directory", map[string]any{
+		"files":      files,
+		"file_bytes": size,
+	})
+
 	pipeReader, pipeWriter := io.Pipe()
 	go func() {
-		err := provisionersdk.Tar(pipeWriter, logger, directory, provisionersdk.TemplateArchiveLimit)
+		err := tarDirectory(ctx, pipeWriter, logger, directory, rules, provisionersdk.TemplateArchiveLimit)
 		_ = pipeWriter.CloseWithError(err)
 	}()
 	defer pipeReader.Close()
-	content := pipeReader
+	content := &uploadProgress{
+		ctx:   ctx,
+		r:     pipeReader,
+		total: size,
+	}
 	resp, err := client.Upload(ctx, codersdk.ContentTypeTar, bufio.NewReader(content))
 	if err != nil {
 		return nil, err
@@ -950,29 +1688,85 @@ func uploadDirectory(ctx context.Context, client *codersdk.Client, logger slog.L
 	return &resp, nil
 }
 
-func waitForJob(ctx context.Context, client *codersdk.Client, version *codersdk.TemplateVersion) error {
-	const maxRetries = 3
+// uploadProgress logs the progress of an upload each time roughly another
+// tenth of the total is read.
+type uploadProgress struct {
+	ctx    context.Context
+	r      io.Reader
+	total  int64
+	read   int64
+	logged int64
+}
+
+func (p *uploadProgress) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.total > 0 && p.read-p.logged >= p.total/10 {
+		p.logged = p.read
+		tflog.Debug(p.ctx, "uploading directory", map[string]any{
+			"sent_bytes": p.read,
+			"file_bytes": p.total,
+		})
+	}
+	return n, err
+}
+
+// jobLogTailLines is the number of provisioner job log lines included in the
+// error when a job fails.
+const jobLogTailLines = 20
+
+// waitForJob waits for the template version's provisioner job to complete.
+// If timeout is zero, the logs are streamed up to 3 times before giving up.
+func waitForJob(ctx context.Context, client *codersdk.Client, version *codersdk.TemplateVersion, timeout time.Duration) error {
+	maxRetries := 3
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+		maxRetries = math.MaxInt
+	}
+	var (
+		// The ID of the last log received, so logs aren't repeated if the
+		// stream is interrupted.
+		after int64
+		tail  []string
+	)
 	for retries := 0; retries < maxRetries; retries++ {
-		logs, closer, err := client.TemplateVersionLogsAfter(ctx, version.ID, 0)
-		defer closer.Close()
+		logs, closer, err := client.TemplateVersionLogsAfter(ctx, version.ID, after)
 		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("provisioner job did not complete within %s", timeout)
+			}
 			return fmt.Errorf("begin streaming logs: %w", err)
 		}
-		for {
-			logs, ok := <-logs
-			if !ok {
-				break
+		for log := range logs {
+			after = log.ID
+			fields := map[string]interface{}{
+				"job_id":     log.ID,
+				"job_stage":  log.Stage,
+				"log_source": log.Source,
+				"level":      log.Level,
+				"created_at": log.CreatedAt,
+			}
+			switch log.Level {
+			case codersdk.LogLevelError:
+				tflog.Error(ctx, log.Output, fields)
+			case codersdk.LogLevelWarn:
+				tflog.Warn(ctx, log.Output, fields)
+			default:
+				tflog.Info(ctx, log.Output, fields)
+			}
+			tail = append(tail, formatJobLog(log))
+			if len(tail) > jobLogTailLines {
+				tail = tail[1:]
 			}
-			tflog.Info(ctx, logs.Output, map[string]interface{}{
-				"job_id":     logs.ID,
-				"job_stage":  logs.Stage,
-				"log_source": logs.Source,
-				"level":      logs.Level,
-				"created_at": logs.CreatedAt,
-			})
 		}
+		_ = closer.Close()
 		latestResp, err := client.TemplateVersion(ctx, version.ID)
 		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("provisioner job did not complete within %s", timeout)
+			}
 			return err
 		}
 		if latestResp.Job.Status.Active() {
@@ -980,23 +1774,226 @@ func waitForJob(ctx context.Context, client *codersdk.Client, version *codersdk.
 			continue
 		}
 		if latestResp.Job.Status != codersdk.ProvisionerJobSucceeded {
-			return fmt.Errorf("provisioner job did not succeed: %s (%s)", latestResp.Job.Status, latestResp.Job.Error)
+			msg := fmt.Sprintf("provisioner job did not succeed: %s (%s)", latestResp.Job.Status, latestResp.Job.Error)
+			if len(tail) > 0 {
+				msg += fmt.Sprintf("\n\nLast %d lines of the provisioner job logs:\n%s", len(tail), strings.Join(tail, "\n"))
+			}
+			return errors.New(msg)
 		}
 		return nil
 	}
 	return fmt.Errorf("provisioner job did not complete after %d retries", maxRetries)
 }
 
+// templateWorkspaces returns the workspaces created from the template.
+func templateWorkspaces(ctx context.Context, client *codersdk.Client, templateID uuid.UUID, templateName string) ([]codersdk.Workspace, error) {
+	workspaces, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.Workspace, error) {
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Template: templateName,
+			Offset:   page.Offset,
+			Limit:    page.Limit,
+		})
+		return res.Workspaces, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The filter matches templates with the same name in other organizations.
+	matching := make([]codersdk.Workspace, 0, len(workspaces))
+	for _, ws := range workspaces {
+		if ws.TemplateID == templateID {
+			matching = append(matching, ws)
+		}
+	}
+	return matching, nil
+}
+
+// workspaceBuildPollInterval is how often a workspace build is checked while
+// waiting for it to complete.
+const workspaceBuildPollInterval = 2 * time.Second
+
+// deleteWorkspace starts a build deleting the workspace, and waits for it to
+// complete. If orphan is true, the workspace's resources are left in place.
+// If timeout is zero, it waits until the context is done.
+func deleteWorkspace(ctx context.Context, client *codersdk.Client, ws codersdk.Workspace, orphan bool, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	tflog.Info(ctx, "deleting workspace", map[string]any{
+		"id":     ws.ID.String(),
+		"owner":  ws.OwnerName,
+		"name":   ws.Name,
+		"orphan": orphan,
+	})
+	build, err := client.CreateWorkspaceBuild(ctx, ws.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionDelete,
+		Orphan:     orphan,
+	})
+	if err != nil {
+		return err
+	}
+	ticker := time.NewTicker(workspaceBuildPollInterval)
+	defer ticker.Stop()
+	for build.Job.Status.Active() {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && timeout > 0 {
+				return fmt.Errorf("delete build did not complete within %s", timeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		build, err = client.WorkspaceBuild(ctx, build.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if build.Job.Status != codersdk.ProvisionerJobSucceeded {
+		return fmt.Errorf("delete build did not succeed: %s (%s)", build.Job.Status, build.Job.Error)
+	}
+	return nil
+}
+
+// versionFingerprintPrefix precedes the fingerprint appended to the messages
+// of template versions.
+const versionFingerprintPrefix = "terraform-fingerprint: "
+
+// versionFingerprint identifies the contents of a template version: its
+// directory and provisioner tags. Versions with Terraform variables have no
+// fingerprint, as their values may be sensitive.
+func versionFingerprint(version *TemplateVersion, defaultTags map[string]string) string {
+	if len(version.TerraformVariables) > 0 || version.DirectoryHash.IsUnknown() || version.DirectoryHash.IsNull() {
+		return ""
+	}
+	tags := mergeProvisionerTags(defaultTags, version.ProvisionerTags)
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	hash := sha256.New()
+	_, _ = fmt.Fprintln(hash, version.DirectoryHash.ValueString())
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:%s\n", len(key), key, len(tags[key]), tags[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// withVersionFingerprint appends the fingerprint to the message.
+func withVersionFingerprint(message, fingerprint string) string {
+	if fingerprint == "" {
+		return message
+	}
+	if message == "" {
+		return versionFingerprintPrefix + fingerprint
+	}
+	return message + "\n\n" + versionFingerprintPrefix + fingerprint
+}
+
+// splitVersionMessage returns the message and fingerprint of a template
+// version, if it has one.
+func splitVersionMessage(message string) (string, string) {
+	idx := strings.LastIndex(message, versionFingerprintPrefix)
+	if idx < 0 || (idx > 0 && !strings.HasSuffix(message[:idx], "\n\n")) {
+		return message, ""
+	}
+	fingerprint := message[idx+len(versionFingerprintPrefix):]
+	if fingerprint == "" || strings.ContainsAny(fingerprint, " \n") {
+		return message, ""
+	}
+	return strings.TrimSuffix(message[:idx], "\n\n"), fingerprint
+}
+
+// matchesRemoteVersion returns whether the remote version was pushed from the
+// same contents as the planned version, with the same name and message, so
+// it can be used instead of pushing a new version.
+func matchesRemoteVersion(remote codersdk.TemplateVersion, planned *TemplateVersion, defaultTags map[string]string) bool {
+	message, fingerprint := splitVersionMessage(remote.Message)
+	if fingerprint == "" || fingerprint != versionFingerprint(planned, defaultTags) {
+		return false
+	}
+	if !planned.Name.IsUnknown() && !planned.Name.IsNull() && planned.Name.ValueString() != remote.Name {
+		return false
+	}
+	return planned.Message.ValueString() == message
+}
+
+// latestTemplateVersion returns the most recently created version of the
+// template.
+func latestTemplateVersion(ctx context.Context, client *codersdk.Client, templateID uuid.UUID) (*codersdk.TemplateVersion, error) {
+	versions, err := listTemplateVersions(ctx, client, templateID)
+	if err != nil {
+		return nil, err
+	}
+	var latest *codersdk.TemplateVersion
+	for _, version := range versions {
+		if latest == nil || version.CreatedAt.After(latest.CreatedAt) {
+			latest = &version
+		}
+	}
+	return latest, nil
+}
+
+// listTemplateVersions returns the versions of the template, including
+// archived versions, keyed by ID.
+func listTemplateVersions(ctx context.Context, client *codersdk.Client, templateID uuid.UUID) (map[uuid.UUID]codersdk.TemplateVersion, error) {
+	versions, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.TemplateVersion, error) {
+		return client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID:      templateID,
+			IncludeArchived: true,
+			Pagination:      page,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]codersdk.TemplateVersion, len(versions))
+	for _, version := range versions {
+		byID[version.ID] = version
+	}
+	return byID, nil
+}
+
+// formatJobLog formats a provisioner job log line for display in diagnostics.
+func formatJobLog(log codersdk.ProvisionerJobLog) string {
+	if log.Stage == "" {
+		return log.Output
+	}
+	return fmt.Sprintf("[%s] %s", log.Stage, log.Output)
+}
+
 type newVersionRequest struct {
 	OrganizationID uuid.UUID
 	Version        *TemplateVersion
 	TemplateID     *uuid.UUID
+	// JobTimeout is the maximum time to wait for the template version to
+	// build, if non-zero.
+	JobTimeout time.Duration
+	// DefaultProvisionerTags are merged with the provisioner tags of the
+	// version.
+	DefaultProvisionerTags map[string]string
+	// RecordFingerprint appends the version's fingerprint to its message, so
+	// the version can be reused.
+	RecordFingerprint bool
 }
 
+// newVersion uploads the source of a template version and creates it, waiting
+// for its import job to complete. If the job fails, the created version is
+// returned alongside the error.
 func newVersion(ctx context.Context, client *codersdk.Client, req newVersionRequest) (*codersdk.TemplateVersion, error) {
-	directory := req.Version.Directory.ValueString()
+	directory, cleanup, err := req.Version.sourceDirectory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve template version source: %s", err)
+	}
+	defer cleanup()
+	rules, err := req.Version.ignoreRules(directory)
+	if err != nil {
+		return nil, err
+	}
 	tflog.Info(ctx, "uploading directory")
-	uploadResp, err := uploadDirectory(ctx, client, slog.Make(newTFLogSink(ctx)), directory)
+	uploadResp, err := uploadDirectory(ctx, client, slog.Make(newTFLogSink(ctx)), directory, rules)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload directory: %s", err)
 	}
@@ -1010,22 +2007,34 @@ func newVersion(ctx context.Context, client *codersdk.Client, req newVersionRequ
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse user variable values: %s", err)
 	}
+	// Only log the names of variables, as their values may be sensitive.
+	varNames := make([]string, 0, len(vars))
+	for _, variable := range vars {
+		varNames = append(varNames, variable.Name)
+	}
 	tflog.Info(ctx, "discovered and parsed vars files", map[string]any{
-		"vars": vars,
+		"vars": varNames,
 	})
-	for _, variable := range req.Version.TerraformVariables {
-		vars = append(vars, codersdk.VariableValue{
-			Name:  variable.Name.ValueString(),
-			Value: variable.Value.ValueString(),
-		})
+	vars = mergeVariableValues(vars, req.Version.TerraformVariables)
+	name := req.Version.Name.ValueString()
+	if name == "" && !req.Version.NameTemplate.IsNull() {
+		name, err = renderVersionName(req.Version.NameTemplate.ValueString(), req.Version.DirectoryHash.ValueString(), time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	message := req.Version.Message.ValueString()
+	if req.RecordFingerprint {
+		message = withVersionFingerprint(message, versionFingerprint(req.Version, req.DefaultProvisionerTags))
 	}
 	tmplVerReq := codersdk.CreateTemplateVersionRequest{
-		Name:               req.Version.Name.ValueString(),
-		Message:            req.Version.Message.ValueString(),
+		Name:               name,
+		Message:            message,
 		StorageMethod:      codersdk.ProvisionerStorageMethodFile,
 		Provisioner:        codersdk.ProvisionerTypeTerraform,
 		FileID:             uploadResp.ID,
 		UserVariableValues: vars,
+		ProvisionerTags:    mergeProvisionerTags(req.DefaultProvisionerTags, req.Version.ProvisionerTags),
 	}
 	if req.TemplateID != nil {
 		tmplVerReq.TemplateID = *req.TemplateID
@@ -1036,14 +2045,128 @@ func newVersion(ctx context.Context, client *codersdk.Client, req newVersionRequ
 		return nil, fmt.Errorf("failed to create template version: %s", err)
 	}
 	tflog.Info(ctx, "waiting for template version import job.")
-	err = waitForJob(ctx, client, &versionResp)
+	err = waitForJob(ctx, client, &versionResp, req.JobTimeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to wait for job: %s", err)
+		// The version exists even though its job failed, so return it to
+		// allow callers to clean it up.
+		return &versionResp, fmt.Errorf("failed to wait for job: %s", err)
 	}
 	tflog.Info(ctx, "successfully created template version")
 	return &versionResp, nil
 }
 
+// applyVersionRetention archives the template versions that fall outside of
+// the retention policy, if one is set. Failing to archive a version is not
+// fatal, as it does not affect the state of the template.
+func (r *TemplateResourceModel) applyVersionRetention(ctx context.Context, client *codersdk.Client) (diags diag.Diagnostics) {
+	if r.VersionRetention.IsNull() {
+		return diags
+	}
+	var retention VersionRetention
+	diags.Append(r.VersionRetention.As(ctx, &retention, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return diags
+	}
+
+	templateID := r.ID.ValueUUID()
+	template, err := client.Template(ctx, templateID)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Failed to get template: %s", err))
+		return diags
+	}
+	versions, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.TemplateVersion, error) {
+		return client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+			TemplateID: templateID,
+			Pagination: page,
+		})
+	})
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Failed to list template versions: %s", err))
+		return diags
+	}
+
+	retained := map[uuid.UUID]struct{}{
+		template.ActiveVersionID: {},
+	}
+	for _, version := range r.Versions {
+		retained[version.ID.ValueUUID()] = struct{}{}
+	}
+	toArchive := versionsToArchive(versions, retained, retention, time.Now())
+	tflog.Info(ctx, "applying template version retention policy", map[string]any{
+		"versions":   len(versions),
+		"to_archive": len(toArchive),
+	})
+	for _, versionID := range toArchive {
+		err := client.SetArchiveTemplateVersion(ctx, versionID, true)
+		if err != nil {
+			diags.AddWarning("Client Warning", fmt.Sprintf("Failed to archive template version %s: %s", versionID, err))
+			continue
+		}
+		tflog.Info(ctx, "archived template version", map[string]any{
+			"version_id": versionID.String(),
+		})
+	}
+	return diags
+}
+
+// versionsToArchive returns the IDs of the versions that fall outside of the
+// retention policy, excluding those in retained.
+func versionsToArchive(versions []codersdk.TemplateVersion, retained map[uuid.UUID]struct{}, retention VersionRetention, now time.Time) []uuid.UUID {
+	sorted := slices.Clone(versions)
+	// Newest first
+	slices.SortFunc(sorted, func(a, b codersdk.TemplateVersion) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	var ids []uuid.UUID
+	for i, version := range sorted {
+		if _, ok := retained[version.ID]; ok || version.Archived {
+			continue
+		}
+		withinKeepLast := !retention.KeepLast.IsNull() && int64(i) < retention.KeepLast.ValueInt64()
+		withinMaxAge := !retention.MaxAgeMillis.IsNull() &&
+			now.Sub(version.CreatedAt) < time.Duration(retention.MaxAgeMillis.ValueInt64())*time.Millisecond
+		if withinKeepLast || withinMaxAge {
+			continue
+		}
+		ids = append(ids, version.ID)
+	}
+	return ids
+}
+
+// mergeVariableValues returns the variable values discovered in vars files,
+// overridden by the variables set in Terraform.
+func mergeVariableValues(fileVars []codersdk.VariableValue, tfVars []Variable) []codersdk.VariableValue {
+	vars := slices.Clone(fileVars)
+	for _, variable := range tfVars {
+		value := codersdk.VariableValue{
+			Name:  variable.Name.ValueString(),
+			Value: variable.Value.ValueString(),
+		}
+		idx := slices.IndexFunc(vars, func(v codersdk.VariableValue) bool {
+			return v.Name == value.Name
+		})
+		if idx >= 0 {
+			vars[idx] = value
+		} else {
+			vars = append(vars, value)
+		}
+	}
+	return vars
+}
+
+// mergeProvisionerTags returns the default provisioner tags, overridden by the
+// tags set on the version.
+func mergeProvisionerTags(defaults map[string]string, tags []Variable) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(tags))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for _, tag := range tags {
+		merged[tag.Name.ValueString()] = tag.Value.ValueString()
+	}
+	return merged
+}
+
 func markActive(ctx context.Context, client *codersdk.Client, templateID uuid.UUID, versionID uuid.UUID) error {
 	tflog.Info(ctx, "marking template version as active", map[string]any{
 		"version_id":  versionID.String(),
@@ -1086,7 +2209,46 @@ func convertACLToRequest(curACL codersdk.TemplateACL, newACL ACL) codersdk.Updat
 	}
 }
 
-func convertResponseToACL(acl codersdk.TemplateACL) ACL {
+// filterTemplateACL returns the users and groups in the ACL that are also in
+// the managed ACL.
+func filterTemplateACL(acl codersdk.TemplateACL, managed ACL) codersdk.TemplateACL {
+	userIDs := make(map[string]struct{}, len(managed.UserPermissions))
+	for _, perm := range managed.UserPermissions {
+		userIDs[perm.ID.ValueString()] = struct{}{}
+	}
+	groupIDs := make(map[string]struct{}, len(managed.GroupPermissions))
+	for _, perm := range managed.GroupPermissions {
+		groupIDs[perm.ID.ValueString()] = struct{}{}
+	}
+	filtered := codersdk.TemplateACL{
+		Users:  make([]codersdk.TemplateUser, 0, len(acl.Users)),
+		Groups: make([]codersdk.TemplateGroup, 0, len(acl.Groups)),
+	}
+	for _, user := range acl.Users {
+		if _, ok := userIDs[user.ID.String()]; ok {
+			filtered.Users = append(filtered.Users, user)
+		}
+	}
+	for _, group := range acl.Groups {
+		if _, ok := groupIDs[group.ID.String()]; ok {
+			filtered.Groups = append(filtered.Groups, group)
+		}
+	}
+	return filtered
+}
+
+// permissionIDs returns the known IDs of the permissions.
+func permissionIDs(perms []Permission) []string {
+	ids := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		if !perm.ID.IsUnknown() && !perm.ID.IsNull() {
+			ids = append(ids, perm.ID.ValueString())
+		}
+	}
+	return ids
+}
+
+func convertResponseToACL(acl codersdk.TemplateACL, mode types.String) ACL {
 	userPerms := make([]Permission, 0, len(acl.Users))
 	for _, user := range acl.Users {
 		userPerms = append(userPerms, Permission{
@@ -1104,9 +2266,20 @@ func convertResponseToACL(acl codersdk.TemplateACL) ACL {
 	return ACL{
 		UserPermissions:  userPerms,
 		GroupPermissions: groupPerms,
+		Mode:             mode,
 	}
 }
 
+// savePartialState saves the template and the versions pushed so far, after
+// creating the template failed part way through and it couldn't be deleted.
+// The versions that weren't pushed are saved without IDs and left out of the
+// private state.
+func (r *TemplateResourceModel) savePartialState(ctx context.Context, templateID uuid.UUID, resp *resource.CreateResponse) {
+	r.ID = UUIDValue(templateID)
+	resp.Diagnostics.Append(r.Versions.setPrivateState(ctx, resp.Private)...)
+	savePartialCreate(ctx, resp, "template", r)
+}
+
 func (r *TemplateResourceModel) readResponse(ctx context.Context, template *codersdk.Template) diag.Diagnostics {
 	r.Name = types.StringValue(template.Name)
 	r.DisplayName = types.StringValue(template.DisplayName)
@@ -1115,6 +2288,7 @@ func (r *TemplateResourceModel) readResponse(ctx context.Context, template *code
 	r.Icon = types.StringValue(template.Icon)
 	r.DefaultTTLMillis = types.Int64Value(template.DefaultTTLMillis)
 	r.ActivityBumpMillis = types.Int64Value(template.ActivityBumpMillis)
+	r.ActivityBump = durationValueFromMillis(r.ActivityBump, template.ActivityBumpMillis)
 	asrObj, diag := types.ObjectValueFrom(ctx, autostopRequirementTypeAttr, AutostopRequirement{
 		DaysOfWeek: template.AutostopRequirement.DaysOfWeek,
 		Weeks:      template.AutostopRequirement.Weeks,
@@ -1134,33 +2308,41 @@ func (r *TemplateResourceModel) readResponse(ctx context.Context, template *code
 	r.FailureTTLMillis = types.Int64Value(template.FailureTTLMillis)
 	r.TimeTilDormantMillis = types.Int64Value(template.TimeTilDormantMillis)
 	r.TimeTilDormantAutoDeleteMillis = types.Int64Value(template.TimeTilDormantAutoDeleteMillis)
+	r.FailureTTL = durationValueFromMillis(r.FailureTTL, template.FailureTTLMillis)
+	r.TimeTilDormant = durationValueFromMillis(r.TimeTilDormant, template.TimeTilDormantMillis)
+	r.TimeTilDormantAutoDelete = durationValueFromMillis(r.TimeTilDormantAutoDelete, template.TimeTilDormantAutoDeleteMillis)
 	r.RequireActiveVersion = types.BoolValue(template.RequireActiveVersion)
 	r.DeprecationMessage = types.StringValue(template.DeprecationMessage)
+	r.MaxPortShareLevel = types.StringValue(string(template.MaxPortShareLevel))
 	return nil
 }
 
-func (r *TemplateResourceModel) toUpdateRequest(ctx context.Context, resp *resource.UpdateResponse) *codersdk.UpdateTemplateMeta {
+func (r *TemplateResourceModel) toUpdateRequest(ctx context.Context, diags *diag.Diagnostics) *codersdk.UpdateTemplateMeta {
 	var days []string
-	resp.Diagnostics.Append(
+	diags.Append(
 		r.AutostartPermittedDaysOfWeek.ElementsAs(ctx, &days, false)...,
 	)
-	if resp.Diagnostics.HasError() {
+	if diags.HasError() {
 		return nil
 	}
 	autoStart := &codersdk.TemplateAutostartRequirement{
 		DaysOfWeek: days,
 	}
 	var reqs AutostopRequirement
-	resp.Diagnostics.Append(
+	diags.Append(
 		r.AutostopRequirement.As(ctx, &reqs, basetypes.ObjectAsOptions{})...,
 	)
-	if resp.Diagnostics.HasError() {
+	if diags.HasError() {
 		return nil
 	}
 	autoStop := &codersdk.TemplateAutostopRequirement{
 		DaysOfWeek: reqs.DaysOfWeek,
 		Weeks:      reqs.Weeks,
 	}
+	var portShareLevel *codersdk.WorkspaceAgentPortShareLevel
+	if !r.MaxPortShareLevel.IsUnknown() && !r.MaxPortShareLevel.IsNull() {
+		portShareLevel = PtrTo(codersdk.WorkspaceAgentPortShareLevel(r.MaxPortShareLevel.ValueString()))
+	}
 	return &codersdk.UpdateTemplateMeta{
 		Name:                           r.Name.ValueString(),
 		DisplayName:                    r.DisplayName.ValueString(),
@@ -1178,8 +2360,9 @@ func (r *TemplateResourceModel) toUpdateRequest(ctx context.Context, resp *resou
 		TimeTilDormantAutoDeleteMillis: r.TimeTilDormantAutoDeleteMillis.ValueInt64(),
 		RequireActiveVersion:           r.RequireActiveVersion.ValueBool(),
 		DeprecationMessage:             r.DeprecationMessage.ValueStringPointer(),
-		// If we're managing ACL, we want to delete the everyone group
-		DisableEveryoneGroupAccess: !r.ACL.IsNull(),
+		MaxPortShareLevel:              portShareLevel,
+		// If we're managing the entire ACL, we want to delete the everyone group
+		DisableEveryoneGroupAccess: r.replacesACL(),
 	}
 }
 
@@ -1222,7 +2405,7 @@ func (r *TemplateResourceModel) toCreateRequest(ctx context.Context, resp *resou
 		TimeTilDormantMillis:           r.TimeTilDormantMillis.ValueInt64Pointer(),
 		TimeTilDormantAutoDeleteMillis: r.TimeTilDormantAutoDeleteMillis.ValueInt64Pointer(),
 		RequireActiveVersion:           r.RequireActiveVersion.ValueBool(),
-		DisableEveryoneGroupAccess:     !r.ACL.IsNull(),
+		DisableEveryoneGroupAccess:     r.replacesACL(),
 	}
 }
 
@@ -1243,6 +2426,11 @@ type privateState interface {
 func (v Versions) setPrivateState(ctx context.Context, ps privateState) (diags diag.Diagnostics) {
 	lv := make(LastVersionsByHash)
 	for _, version := range v {
+		// Versions that haven't been pushed are left out, so they're pushed
+		// when next applied.
+		if version.ID.IsNull() || version.ID.IsUnknown() {
+			continue
+		}
 		vbh, ok := lv[version.DirectoryHash.ValueString()]
 		// Store the IDs and names of all versions with the same directory hash,
 		// in the order they appear
@@ -1268,6 +2456,86 @@ func (v Versions) setPrivateState(ctx context.Context, ps privateState) (diags d
 	return ps.SetKey(ctx, LastVersionsKey, lvBytes)
 }
 
+// updateVersionMetadata updates the names and messages of the existing
+// versions that changed. Template version names must be unique, so if a
+// version is renamed to the current name of another, such as when swapping
+// names, the renamed versions are first given temporary names.
+func updateVersionMetadata(ctx context.Context, client *codersdk.Client, curVersions, planVersions Versions) (diags diag.Diagnostics) {
+	curNames := make(map[string]UUID, len(curVersions))
+	for _, version := range curVersions {
+		curNames[version.Name.ValueString()] = version.ID
+	}
+
+	var changed, renamed []*TemplateVersion
+	conflict := false
+	for idx := range planVersions {
+		version := &planVersions[idx]
+		if version.ID.IsUnknown() {
+			continue
+		}
+		curVersion := curVersions.ByID(version.ID)
+		if curVersion == nil {
+			// Reported when the versions are pushed
+			continue
+		}
+		if !curVersion.Name.Equal(version.Name) {
+			renamed = append(renamed, version)
+			if id, ok := curNames[version.Name.ValueString()]; ok && !id.Equal(version.ID) {
+				conflict = true
+			}
+		} else if curVersion.Message.Equal(version.Message) {
+			continue
+		}
+		changed = append(changed, version)
+	}
+
+	if conflict {
+		for _, version := range renamed {
+			tmpName := "renaming-" + version.ID.ValueString()
+			tflog.Info(ctx, "temporarily renaming template version", map[string]any{
+				"id":   version.ID.ValueString(),
+				"name": tmpName,
+			})
+			_, err := client.UpdateTemplateVersion(ctx, version.ID.ValueUUID(), codersdk.PatchTemplateVersionRequest{
+				Name: tmpName,
+			})
+			if err != nil {
+				diags.AddError("Client Error", fmt.Sprintf("Failed to update template version metadata: %s", err))
+				return diags
+			}
+		}
+	}
+	for _, version := range changed {
+		_, err := client.UpdateTemplateVersion(ctx, version.ID.ValueUUID(), codersdk.PatchTemplateVersionRequest{
+			Name:    version.Name.ValueString(),
+			Message: version.Message.ValueStringPointer(),
+		})
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Failed to update template version metadata: %s", err))
+			return diags
+		}
+	}
+	return diags
+}
+
+// migrateLegacyHash moves the versions stored under the legacy hash of a
+// directory to its current hash, so versions pushed by previous versions of
+// the provider aren't pushed again.
+func migrateLegacyHash(lv LastVersionsByHash, hash string, legacyHash func() (string, error)) error {
+	if _, ok := lv[hash]; ok {
+		return nil
+	}
+	legacy, err := legacyHash()
+	if err != nil {
+		return err
+	}
+	if prev, ok := lv[legacy]; ok {
+		lv[hash] = prev
+		delete(lv, legacy)
+	}
+	return nil
+}
+
 func (planVersions Versions) reconcileVersionIDs(lv LastVersionsByHash, configVersions Versions) {
 	for i := range planVersions {
 		prevList, ok := lv[planVersions[i].DirectoryHash.ValueString()]
@@ -1309,3 +2577,96 @@ func (planVersions Versions) reconcileVersionIDs(lv LastVersionsByHash, configVe
 		}
 	}
 }
+
+// templateExtraSettings holds the settings of a template that aren't yet
+// available in codersdk.Template.
+type templateExtraSettings struct {
+	UseClassicParameterFlow *bool   `json:"use_classic_parameter_flow"`
+	CORSBehavior            *string `json:"cors_behavior"`
+}
+
+// readExtraSettings reads the settings that aren't yet available in
+// codersdk.Template into the model. Deployments that don't support a setting
+// don't return it, in which case it's null.
+func (r *TemplateResourceModel) readExtraSettings(ctx context.Context, client *codersdk.Client, templateID uuid.UUID) error {
+	res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s", templateID), nil)
+	if err != nil {
+		return fmt.Errorf("Failed to get template settings: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed to get template settings: %w", codersdk.ReadBodyAsError(res))
+	}
+	var settings templateExtraSettings
+	err = json.NewDecoder(res.Body).Decode(&settings)
+	if err != nil {
+		return fmt.Errorf("Failed to decode template settings: %w", err)
+	}
+
+	if settings.UseClassicParameterFlow == nil {
+		if !r.UseClassicParameterFlow.IsNull() && !r.UseClassicParameterFlow.IsUnknown() {
+			return fmt.Errorf("use_classic_parameter_flow is not supported by this Coder deployment")
+		}
+		r.UseClassicParameterFlow = types.BoolNull()
+	} else {
+		r.UseClassicParameterFlow = types.BoolValue(*settings.UseClassicParameterFlow)
+	}
+
+	if settings.CORSBehavior == nil {
+		if !r.CORSBehavior.IsNull() && !r.CORSBehavior.IsUnknown() {
+			return fmt.Errorf("cors_behavior is not supported by this Coder deployment")
+		}
+		r.CORSBehavior = types.StringNull()
+	} else {
+		r.CORSBehavior = types.StringValue(*settings.CORSBehavior)
+	}
+	return nil
+}
+
+// extraUpdateFields returns the known settings that aren't yet available in
+// codersdk.UpdateTemplateMeta.
+func (r *TemplateResourceModel) extraUpdateFields() map[string]any {
+	fields := map[string]any{}
+	if !r.UseClassicParameterFlow.IsNull() && !r.UseClassicParameterFlow.IsUnknown() {
+		fields["use_classic_parameter_flow"] = r.UseClassicParameterFlow.ValueBool()
+	}
+	if !r.CORSBehavior.IsNull() && !r.CORSBehavior.IsUnknown() {
+		fields["cors_behavior"] = r.CORSBehavior.ValueString()
+	}
+	return fields
+}
+
+// updateTemplateMeta updates the template metadata, including any extra
+// fields codersdk doesn't support yet.
+func updateTemplateMeta(ctx context.Context, client *codersdk.Client, templateID uuid.UUID, req codersdk.UpdateTemplateMeta, extra map[string]any) (codersdk.Template, error) {
+	if len(extra) == 0 {
+		return client.UpdateTemplateMeta(ctx, templateID, req)
+	}
+	// Add the extra fields to the request codersdk would otherwise send.
+	body, err := json.Marshal(req)
+	if err != nil {
+		return codersdk.Template{}, err
+	}
+	var fields map[string]any
+	err = json.Unmarshal(body, &fields)
+	if err != nil {
+		return codersdk.Template{}, err
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+
+	res, err := client.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s", templateID), fields)
+	if err != nil {
+		return codersdk.Template{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified {
+		return client.Template(ctx, templateID)
+	}
+	if res.StatusCode != http.StatusOK {
+		return codersdk.Template{}, codersdk.ReadBodyAsError(res)
+	}
+	var template codersdk.Template
+	return template, json.NewDecoder(res.Body).Decode(&template)
+}
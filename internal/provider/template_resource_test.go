@@ -2,13 +2,19 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -60,6 +66,10 @@ func TestAccTemplateResource(t *testing.T) {
 		cfg2.Name = PtrTo("example-template-new")
 		cfg2.Versions[0].Directory = &exTemplateTwo
 		cfg2.Versions[0].Name = PtrTo("new")
+		cfg2.ActivityBumpDuration = PtrTo("4h")
+		cfg2.UseClassicParameterFlow = PtrTo(true)
+		cfg2.CORSBehavior = PtrTo("passthru")
+		cfg2.AllowUserCancelWorkspaceJobs = PtrTo(false)
 
 		cfg3 := cfg2
 		cfg3.Versions = slices.Clone(cfg3.Versions)
@@ -75,6 +85,8 @@ func TestAccTemplateResource(t *testing.T) {
 			},
 		})
 
+		cfg3.ValidateVersions = PtrTo(true)
+
 		cfg4 := cfg3
 		cfg4.Versions = slices.Clone(cfg4.Versions)
 		cfg4.Versions[0].Active = PtrTo(false)
@@ -103,6 +115,7 @@ func TestAccTemplateResource(t *testing.T) {
 						resource.TestCheckResourceAttr("coderd_template.test", "icon", ""),
 						resource.TestCheckResourceAttr("coderd_template.test", "default_ttl_ms", "0"),
 						resource.TestCheckResourceAttr("coderd_template.test", "activity_bump_ms", "3600000"),
+						resource.TestCheckResourceAttr("coderd_template.test", "activity_bump", "1h0m0s"),
 						resource.TestCheckResourceAttr("coderd_template.test", "auto_stop_requirement.days_of_week.#", "0"),
 						resource.TestCheckResourceAttr("coderd_template.test", "auto_stop_requirement.weeks", "1"),
 						resource.TestCheckResourceAttr("coderd_template.test", "auto_start_permitted_days_of_week.#", "7"),
@@ -112,7 +125,11 @@ func TestAccTemplateResource(t *testing.T) {
 						resource.TestCheckResourceAttr("coderd_template.test", "failure_ttl_ms", "0"),
 						resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_ms", "0"),
 						resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_autodelete_ms", "0"),
+						resource.TestCheckResourceAttr("coderd_template.test", "failure_ttl", "0s"),
+						resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant", "0s"),
+						resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_autodelete", "0s"),
 						resource.TestCheckResourceAttr("coderd_template.test", "require_active_version", "false"),
+						resource.TestCheckResourceAttr("coderd_template.test", "max_port_share_level", "public"),
 						resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "versions.*", map[string]*regexp.Regexp{
 							"name":           regexp.MustCompile(".+"),
 							"id":             regexp.MustCompile(".+"),
@@ -152,7 +169,7 @@ func TestAccTemplateResource(t *testing.T) {
 					ImportState:       true,
 					ImportStateVerify: true,
 					// In the real world, `versions` needs to be added to the configuration after importing
-					// We can't import ACL as we can't currently differentiate between managed and unmanaged ACL
+					// The ACL is imported in replace mode, which isn't configured here
 					ImportStateVerifyIgnore: []string{"versions", "acl"},
 				},
 				// Import by org name and template name
@@ -169,6 +186,11 @@ func TestAccTemplateResource(t *testing.T) {
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttrSet("coderd_template.test", "id"),
 						resource.TestCheckResourceAttr("coderd_template.test", "name", "example-template-new"),
+						resource.TestCheckResourceAttr("coderd_template.test", "activity_bump", "4h"),
+						resource.TestCheckResourceAttr("coderd_template.test", "activity_bump_ms", "14400000"),
+						resource.TestCheckResourceAttr("coderd_template.test", "use_classic_parameter_flow", "true"),
+						resource.TestCheckResourceAttr("coderd_template.test", "cors_behavior", "passthru"),
+						resource.TestCheckResourceAttr("coderd_template.test", "allow_user_cancel_workspace_jobs", "false"),
 						resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "versions.*", map[string]*regexp.Regexp{
 							"name": regexp.MustCompile("new"),
 						}),
@@ -180,6 +202,7 @@ func TestAccTemplateResource(t *testing.T) {
 					Config: cfg3.String(t),
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttr("coderd_template.test", "versions.#", "2"),
+						resource.TestCheckResourceAttr("coderd_template.test", "validate_versions", "true"),
 						resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "versions.*", map[string]*regexp.Regexp{
 							"name": regexp.MustCompile("legacy-template"),
 						}),
@@ -346,6 +369,281 @@ func TestAccTemplateResource(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("SwapVersionNames", func(t *testing.T) {
+		cfg1 := testAccTemplateResourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			Name:  PtrTo("swapped-template"),
+			Versions: []testAccTemplateVersionConfig{
+				{
+					Name:      PtrTo("alpha"),
+					Directory: &exTemplateOne,
+					Active:    PtrTo(true),
+				},
+				{
+					Name:      PtrTo("beta"),
+					Directory: &exTemplateTwo,
+					TerraformVariables: []testAccTemplateKeyValueConfig{
+						{
+							Key:   PtrTo("name"),
+							Value: PtrTo("world"),
+						},
+					},
+				},
+			},
+			ACL: testAccTemplateACLConfig{
+				null: true,
+			},
+		}
+
+		cfg2 := cfg1
+		cfg2.Versions = slices.Clone(cfg2.Versions)
+		cfg2.Versions[0].Name = PtrTo("beta")
+		cfg2.Versions[1].Name = PtrTo("alpha")
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg1.String(t),
+					Check:  testAccCheckNumTemplateVersions(ctx, client, 2),
+				},
+				// Swapping names renames the existing versions
+				{
+					Config: cfg2.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_template.test", "versions.0.name", "beta"),
+						resource.TestCheckResourceAttr("coderd_template.test", "versions.0.active", "true"),
+						resource.TestCheckResourceAttr("coderd_template.test", "versions.1.name", "alpha"),
+						resource.TestCheckResourceAttr("coderd_template.test", "versions.1.active", "false"),
+						testAccCheckNumTemplateVersions(ctx, client, 2),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("NameTemplate", func(t *testing.T) {
+		cfg := testAccTemplateResourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			Name:  PtrTo("named-template"),
+			Versions: []testAccTemplateVersionConfig{
+				{
+					NameTemplate: PtrTo("{{.Date}}-{{.Hash}}"),
+					Directory:    &exTemplateOne,
+					Active:       PtrTo(true),
+				},
+			},
+			ACL: testAccTemplateACLConfig{
+				null: true,
+			},
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestMatchResourceAttr("coderd_template.test", "versions.0.name", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-[0-9a-f]{7}$`)),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ActiveVersionID", func(t *testing.T) {
+		// Versions pushed outside of Terraform, e.g. by CI
+		orgID := firstUser.OrganizationIDs[0]
+		stable, err := newVersion(ctx, client, newVersionRequest{
+			OrganizationID: orgID,
+			Version: &TemplateVersion{
+				Name:      types.StringValue("stable"),
+				Directory: types.StringValue(exTemplateOne),
+			},
+		})
+		require.NoError(t, err)
+		tpl, err := client.CreateTemplate(ctx, orgID, codersdk.CreateTemplateRequest{
+			Name:      "pinned-template",
+			VersionID: stable.ID,
+		})
+		require.NoError(t, err)
+		candidate, err := newVersion(ctx, client, newVersionRequest{
+			OrganizationID: orgID,
+			TemplateID:     &tpl.ID,
+			Version: &TemplateVersion{
+				Name:      types.StringValue("candidate"),
+				Directory: types.StringValue(exTemplateTwo),
+			},
+		})
+		require.NoError(t, err)
+
+		cfg1 := testAccTemplateResourceConfig{
+			URL:             client.URL.String(),
+			Token:           client.SessionToken(),
+			Name:            PtrTo("pinned-template"),
+			ActiveVersionID: PtrTo(candidate.ID.String()),
+			Versions: []testAccTemplateVersionConfig{
+				{
+					Directory: &exTemplateOne,
+				},
+			},
+			ACL: testAccTemplateACLConfig{
+				null: true,
+			},
+		}
+
+		cfg2 := cfg1
+		cfg2.ActiveVersionID = PtrTo(stable.ID.String())
+
+		cfg3 := cfg2
+		cfg3.Versions = slices.Clone(cfg3.Versions)
+		cfg3.Versions[0].Active = PtrTo(true)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config:             cfg1.String(t),
+					ResourceName:       "coderd_template.test",
+					ImportState:        true,
+					ImportStateId:      tpl.ID.String(),
+					ImportStatePersist: true,
+					// The active version is imported
+					ImportStateCheck: func(states []*terraform.InstanceState) error {
+						attrs := states[0].Attributes
+						if attrs["versions.#"] != "1" || attrs["versions.0.id"] != stable.ID.String() || attrs["versions.0.name"] != "stable" {
+							return fmt.Errorf("unexpected imported versions %v", attrs)
+						}
+						return nil
+					},
+				},
+				// Pushing a version doesn't make it active
+				{
+					Config: cfg1.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_template.test", "active_version_id", candidate.ID.String()),
+						resource.TestCheckResourceAttr("coderd_template.test", "versions.0.active", "false"),
+						testAccCheckTemplateActiveVersion(ctx, client, tpl.ID, candidate.ID),
+						testAccCheckNumTemplateVersions(ctx, client, 3),
+					),
+				},
+				// Roll back to the previous version
+				{
+					Config: cfg2.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("coderd_template.test", "active_version_id", stable.ID.String()),
+						testAccCheckTemplateActiveVersion(ctx, client, tpl.ID, stable.ID),
+					),
+				},
+				{
+					Config:      cfg3.String(t),
+					ExpectError: regexp.MustCompile("can't be marked active when active_version_id is set"),
+				},
+			},
+		})
+	})
+
+	t.Run("FailedImportLogs", func(t *testing.T) {
+		brokenTemplate := t.TempDir()
+		err := os.WriteFile(filepath.Join(brokenTemplate, "main.tf"), []byte("resource \"not_a_provider_resource\" {\n"), 0o600)
+		require.NoError(t, err)
+
+		cfg := testAccTemplateResourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			Name:  PtrTo("broken-template"),
+			Versions: []testAccTemplateVersionConfig{
+				{
+					Directory: &brokenTemplate,
+					Active:    PtrTo(true),
+				},
+			},
+			ACL: testAccTemplateACLConfig{
+				null: true,
+			},
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					// The failure includes the end of the job logs
+					ExpectError: regexp.MustCompile(`(?s)provisioner job did not succeed.*Last \d+ lines of the provisioner job logs`),
+				},
+			},
+		})
+
+		validCfg := cfg
+		validCfg.Name = PtrTo("validated-template")
+		validCfg.ValidateVersions = PtrTo(true)
+		validCfg.Versions = []testAccTemplateVersionConfig{
+			{
+				Directory: &exTemplateOne,
+				Active:    PtrTo(true),
+			},
+		}
+		validateCfg := validCfg
+		validateCfg.Versions = []testAccTemplateVersionConfig{
+			{
+				Directory: &brokenTemplate,
+				Active:    PtrTo(true),
+			},
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			IsUnitTest:               true,
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				// Versions aren't validated until the template exists
+				{
+					Config: validCfg.String(t),
+					Check:  testAccCheckNumTemplateVersions(ctx, client, 1),
+				},
+				{
+					Config:      validateCfg.String(t),
+					ExpectError: regexp.MustCompile("Template Version Validation Failed"),
+				},
+				// The broken version isn't pushed, and only the version
+				// created for validation when applying, not when planning,
+				// is archived
+				{
+					Config: validCfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						testAccCheckNumTemplateVersions(ctx, client, 1),
+						func(*terraform.State) error {
+							templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+							if err != nil {
+								return err
+							}
+							versions, err := client.TemplateVersionsByTemplate(ctx, codersdk.TemplateVersionsByTemplateRequest{
+								TemplateID:      templates[0].ID,
+								IncludeArchived: true,
+							})
+							if err != nil {
+								return err
+							}
+							if len(versions) != 2 {
+								return fmt.Errorf("expected 2 versions including archived versions, got %d", len(versions))
+							}
+							return nil
+						},
+					),
+				},
+			},
+		})
+	})
 }
 
 func TestAccTemplateResourceEnterprise(t *testing.T) {
@@ -402,10 +700,38 @@ func TestAccTemplateResourceEnterprise(t *testing.T) {
 
 	cfg4 := cfg3
 	cfg4.AllowUserAutostart = PtrTo(false)
+	cfg4.AllowUserAutostop = PtrTo(false)
+	cfg4.AllowUserCancelWorkspaceJobs = PtrTo(false)
 	cfg4.AutostopRequirement = testAccAutostopRequirementConfig{
 		DaysOfWeek: PtrTo([]string{"monday", "tuesday"}),
 		Weeks:      PtrTo(int64(2)),
 	}
+	cfg4.RequireActiveVersion = PtrTo(true)
+	cfg4.MaxPortShareLevel = PtrTo("authenticated")
+	cfg4.TimeTilDormantDuration = PtrTo("168h")
+	cfg4.TimeTilDormantAutodelete = PtrTo(int64(30 * 24 * time.Hour / time.Millisecond))
+
+	cfg5 := cfg4
+	cfg5.DeprecationMessage = PtrTo("Use example-template-v2 instead.")
+
+	cfg6 := cfg5
+	cfg6.DeprecationMessage = nil
+
+	// Manage only the first user, leaving the group untouched
+	cfg7 := cfg6
+	cfg7.ACL = testAccTemplateACLConfig{
+		Mode: PtrTo("merge"),
+		UserACL: []testAccTemplateKeyValueConfig{
+			{
+				Key:   PtrTo(firstUser.ID.String()),
+				Value: PtrTo("use"),
+			},
+		},
+	}
+
+	// Stop managing the first user, which removes them from the ACL
+	cfg8 := cfg7
+	cfg8.ACL.UserACL = nil
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -416,6 +742,7 @@ func TestAccTemplateResourceEnterprise(t *testing.T) {
 				Config: cfg1.String(t),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("coderd_template.test", "acl.groups.#", "2"),
+					resource.TestCheckResourceAttr("coderd_template.test", "max_port_share_level", "owner"),
 					resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "acl.groups.*", map[string]*regexp.Regexp{
 						"id":   regexp.MustCompile(firstUser.OrganizationIDs[0].String()),
 						"role": regexp.MustCompile("^use$"),
@@ -440,6 +767,26 @@ func TestAccTemplateResourceEnterprise(t *testing.T) {
 					}),
 				),
 			},
+			// Changes to the ACL made outside of Terraform are reverted
+			{
+				Config: cfg2.String(t),
+				PreConfig: func() {
+					template := testAccGetTemplate(ctx, t, client)
+					err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+						GroupPerms: map[string]codersdk.TemplateRole{
+							group.ID.String(): codersdk.TemplateRoleUse,
+						},
+					})
+					require.NoError(t, err)
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "acl.groups.#", "1"),
+					resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "acl.groups.*", map[string]*regexp.Regexp{
+						"id":   regexp.MustCompile(group.ID.String()),
+						"role": regexp.MustCompile("^admin$"),
+					}),
+				),
+			},
 			{
 				Config: cfg3.String(t),
 				Check: resource.ComposeAggregateTestCheckFunc(
@@ -476,14 +823,177 @@ func TestAccTemplateResourceEnterprise(t *testing.T) {
 				Config: cfg4.String(t),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("coderd_template.test", "allow_user_auto_start", "false"),
+					resource.TestCheckResourceAttr("coderd_template.test", "allow_user_auto_stop", "false"),
+					resource.TestCheckResourceAttr("coderd_template.test", "allow_user_cancel_workspace_jobs", "false"),
 					resource.TestCheckResourceAttr("coderd_template.test", "auto_stop_requirement.days_of_week.#", "2"),
 					resource.TestCheckResourceAttr("coderd_template.test", "auto_stop_requirement.weeks", "2"),
+					resource.TestCheckResourceAttr("coderd_template.test", "require_active_version", "true"),
+					resource.TestCheckResourceAttr("coderd_template.test", "max_port_share_level", "authenticated"),
+					resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant", "168h"),
+					resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_ms", "604800000"),
+					resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_autodelete", "720h0m0s"),
+					resource.TestCheckResourceAttr("coderd_template.test", "time_til_dormant_autodelete_ms", "2592000000"),
+					func(s *terraform.State) error {
+						templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+						if err != nil {
+							return err
+						}
+						if len(templates) != 1 {
+							return fmt.Errorf("expected 1 template, got %d", len(templates))
+						}
+						if !templates[0].RequireActiveVersion {
+							return fmt.Errorf("expected template to require the active version")
+						}
+						if templates[0].AllowUserAutostart || templates[0].AllowUserAutostop || templates[0].AllowUserCancelWorkspaceJobs {
+							return fmt.Errorf("expected users to be unable to auto-start, auto-stop or cancel jobs")
+						}
+						if templates[0].AutostopRequirement.Weeks != 2 {
+							return fmt.Errorf("expected auto-stop requirement every 2 weeks, got %d", templates[0].AutostopRequirement.Weeks)
+						}
+						if len(templates[0].AutostopRequirement.DaysOfWeek) != 2 {
+							return fmt.Errorf("expected 2 auto-stop requirement days, got %d", len(templates[0].AutostopRequirement.DaysOfWeek))
+						}
+						return nil
+					},
+				),
+			},
+			// Deprecate the template
+			{
+				Config: cfg5.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "deprecation_message", "Use example-template-v2 instead."),
+					testAccCheckTemplateDeprecated(ctx, client, true),
+				),
+			},
+			// Changes to the schedule and deprecation made outside of
+			// Terraform are reverted
+			{
+				Config: cfg5.String(t),
+				PreConfig: func() {
+					template := testAccGetTemplate(ctx, t, client)
+					_, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+						Name:                           template.Name,
+						DisplayName:                    template.DisplayName,
+						Description:                    template.Description,
+						Icon:                           template.Icon,
+						DefaultTTLMillis:               template.DefaultTTLMillis,
+						ActivityBumpMillis:             template.ActivityBumpMillis,
+						AutostopRequirement:            &template.AutostopRequirement,
+						AutostartRequirement:           &template.AutostartRequirement,
+						AllowUserCancelWorkspaceJobs:   template.AllowUserCancelWorkspaceJobs,
+						AllowUserAutostart:             true,
+						AllowUserAutostop:              template.AllowUserAutostop,
+						FailureTTLMillis:               template.FailureTTLMillis,
+						TimeTilDormantMillis:           template.TimeTilDormantMillis,
+						TimeTilDormantAutoDeleteMillis: template.TimeTilDormantAutoDeleteMillis,
+						RequireActiveVersion:           template.RequireActiveVersion,
+						DeprecationMessage:             PtrTo(""),
+						MaxPortShareLevel:              &template.MaxPortShareLevel,
+					})
+					require.NoError(t, err)
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "deprecation_message", "Use example-template-v2 instead."),
+					resource.TestCheckResourceAttr("coderd_template.test", "allow_user_auto_start", "false"),
+					testAccCheckTemplateDeprecated(ctx, client, true),
+				),
+			},
+			// Un-deprecate the template
+			{
+				Config: cfg6.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "deprecation_message", ""),
+					testAccCheckTemplateDeprecated(ctx, client, false),
+				),
+			},
+			// Merge the ACL
+			{
+				Config: cfg7.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "acl.mode", "merge"),
+					resource.TestCheckResourceAttr("coderd_template.test", "acl.groups.#", "0"),
+					resource.TestMatchTypeSetElemNestedAttrs("coderd_template.test", "acl.users.*", map[string]*regexp.Regexp{
+						"id":   regexp.MustCompile(firstUser.ID.String()),
+						"role": regexp.MustCompile("^use$"),
+					}),
+					testAccCheckTemplateACLSize(ctx, client, 1, 1),
+				),
+			},
+			// Remove the merged user
+			{
+				Config: cfg8.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "acl.users.#", "0"),
+					testAccCheckTemplateACLSize(ctx, client, 0, 1),
+				),
+			},
+		},
+	})
+
+	// Deprecated on creation
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg5.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_template.test", "deprecation_message", "Use example-template-v2 instead."),
+					testAccCheckTemplateDeprecated(ctx, client, true),
 				),
 			},
 		},
 	})
 }
 
+// testAccGetTemplate returns the only template in the deployment.
+func testAccGetTemplate(ctx context.Context, t *testing.T, client *codersdk.Client) codersdk.Template {
+	templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	return templates[0]
+}
+
+func testAccCheckTemplateDeprecated(ctx context.Context, client *codersdk.Client, deprecated bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+		if err != nil {
+			return err
+		}
+		if len(templates) != 1 {
+			return fmt.Errorf("expected 1 template, got %d", len(templates))
+		}
+		if templates[0].Deprecated != deprecated {
+			return fmt.Errorf("expected template deprecated to be %t, got %t", deprecated, templates[0].Deprecated)
+		}
+		return nil
+	}
+}
+
+func testAccCheckTemplateACLSize(ctx context.Context, client *codersdk.Client, users, groups int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
+		if err != nil {
+			return err
+		}
+		if len(templates) != 1 {
+			return fmt.Errorf("expected 1 template, got %d", len(templates))
+		}
+		acl, err := client.TemplateACL(ctx, templates[0].ID)
+		if err != nil {
+			return err
+		}
+		if len(acl.Users) != users {
+			return fmt.Errorf("expected %d users in template ACL, got %d", users, len(acl.Users))
+		}
+		if len(acl.Groups) != groups {
+			return fmt.Errorf("expected %d groups in template ACL, got %d", groups, len(acl.Groups))
+		}
+		return nil
+	}
+}
+
 func TestAccTemplateResourceAGPL(t *testing.T) {
 	if os.Getenv("TF_ACC") == "" {
 		t.Skip("Acceptance tests are disabled.")
@@ -523,6 +1033,14 @@ func TestAccTemplateResourceAGPL(t *testing.T) {
 	cfg5.AutostartRequirement = nil
 	cfg5.RequireActiveVersion = PtrTo(true)
 
+	cfg7 := cfg5
+	cfg7.RequireActiveVersion = nil
+	cfg7.DeprecationMessage = PtrTo("Deprecated")
+
+	cfg8 := cfg5
+	cfg8.RequireActiveVersion = nil
+	cfg8.MaxPortShareLevel = PtrTo("owner")
+
 	cfg6 := cfg5
 	cfg6.RequireActiveVersion = nil
 	cfg6.ACL = testAccTemplateACLConfig{
@@ -534,6 +1052,14 @@ func TestAccTemplateResourceAGPL(t *testing.T) {
 		},
 	}
 
+	cfg9 := cfg6
+	cfg9.ACL.GroupACL = []testAccTemplateKeyValueConfig{
+		{
+			Key:   PtrTo(firstUser.OrganizationIDs[0].String()),
+			Value: PtrTo("owner"),
+		},
+	}
+
 	for _, cfg := range []testAccTemplateResourceConfig{cfg1, cfg2, cfg3, cfg4} {
 		resource.Test(t, resource.TestCase{
 			PreCheck:                 func() { testAccPreCheck(t) },
@@ -561,6 +1087,18 @@ func TestAccTemplateResourceAGPL(t *testing.T) {
 				Config:      cfg6.String(t),
 				ExpectError: regexp.MustCompile("Your license is not entitled to use template access control"),
 			},
+			{
+				Config:      cfg7.String(t),
+				ExpectError: regexp.MustCompile("Your license is not entitled to use access control"),
+			},
+			{
+				Config:      cfg8.String(t),
+				ExpectError: regexp.MustCompile("Your license is not entitled to use port sharing control"),
+			},
+			{
+				Config:      cfg9.String(t),
+				ExpectError: regexp.MustCompile(`value must be one of: \["use" "admin"\]`),
+			},
 		},
 	})
 }
@@ -569,23 +1107,32 @@ type testAccTemplateResourceConfig struct {
 	URL   string
 	Token string
 
-	Name                         *string
-	DisplayName                  *string
-	Description                  *string
-	OrganizationID               *string
-	Icon                         *string
-	DefaultTTL                   *int64
-	ActivityBump                 *int64
-	AutostopRequirement          testAccAutostopRequirementConfig
-	AutostartRequirement         *[]string
-	AllowUserCancelWorkspaceJobs *bool
-	AllowUserAutostart           *bool
-	AllowUserAutostop            *bool
-	FailureTTL                   *int64
-	TimeTilDormant               *int64
-	TimeTilDormantAutodelete     *int64
-	RequireActiveVersion         *bool
-	DeprecationMessage           *string
+	Name                             *string
+	DisplayName                      *string
+	Description                      *string
+	OrganizationID                   *string
+	Icon                             *string
+	DefaultTTL                       *int64
+	ActivityBump                     *int64
+	ActivityBumpDuration             *string
+	AutostopRequirement              testAccAutostopRequirementConfig
+	AutostartRequirement             *[]string
+	AllowUserCancelWorkspaceJobs     *bool
+	AllowUserAutostart               *bool
+	AllowUserAutostop                *bool
+	FailureTTL                       *int64
+	TimeTilDormant                   *int64
+	TimeTilDormantAutodelete         *int64
+	FailureTTLDuration               *string
+	TimeTilDormantDuration           *string
+	TimeTilDormantAutodeleteDuration *string
+	RequireActiveVersion             *bool
+	DeprecationMessage               *string
+	MaxPortShareLevel                *string
+	UseClassicParameterFlow          *bool
+	CORSBehavior                     *string
+	ValidateVersions                 *bool
+	ActiveVersionID                  *string
 
 	Versions []testAccTemplateVersionConfig
 	ACL      testAccTemplateACLConfig
@@ -595,6 +1142,7 @@ type testAccTemplateACLConfig struct {
 	null     bool
 	GroupACL []testAccTemplateKeyValueConfig
 	UserACL  []testAccTemplateKeyValueConfig
+	Mode     *string
 }
 
 func (c testAccTemplateACLConfig) String(t *testing.T) string {
@@ -619,6 +1167,7 @@ func (c testAccTemplateACLConfig) String(t *testing.T) string {
 			},
 			{{- end}}
 		]
+		mode = {{orNull .Mode}}
 	}
 	`
 
@@ -682,6 +1231,7 @@ resource "coderd_template" "test" {
 	icon                              = {{orNull .Icon}}
 	default_ttl_ms                    = {{orNull .DefaultTTL}}
 	activity_bump_ms                  = {{orNull .ActivityBump}}
+	activity_bump                     = {{orNull .ActivityBumpDuration}}
 	auto_stop_requirement             = ` + c.AutostopRequirement.String(t) + `
 	auto_start_permitted_days_of_week = {{orNull .AutostartRequirement}}
 	allow_user_cancel_workspace_jobs  = {{orNull .AllowUserCancelWorkspaceJobs}}
@@ -690,17 +1240,26 @@ resource "coderd_template" "test" {
 	failure_ttl_ms                    = {{orNull .FailureTTL}}
 	time_til_dormant_ms               = {{orNull .TimeTilDormant}}
 	time_til_dormant_autodelete_ms    = {{orNull .TimeTilDormantAutodelete}}
+	failure_ttl                       = {{orNull .FailureTTLDuration}}
+	time_til_dormant                  = {{orNull .TimeTilDormantDuration}}
+	time_til_dormant_autodelete       = {{orNull .TimeTilDormantAutodeleteDuration}}
 	require_active_version            = {{orNull .RequireActiveVersion}}
 	deprecation_message               = {{orNull .DeprecationMessage}}
+	max_port_share_level              = {{orNull .MaxPortShareLevel}}
+	use_classic_parameter_flow        = {{orNull .UseClassicParameterFlow}}
+	cors_behavior                     = {{orNull .CORSBehavior}}
+	validate_versions                 = {{orNull .ValidateVersions}}
+	active_version_id                 = {{orNull .ActiveVersionID}}
 
 	acl = ` + c.ACL.String(t) + `
 
 	versions = [
 	{{- range .Versions }}
 	{
-		name      = {{orNull .Name}}
-		directory = {{orNull .Directory}}
-		active    = {{orNull .Active}}
+		name          = {{orNull .Name}}
+		name_template = {{orNull .NameTemplate}}
+		directory     = {{orNull .Directory}}
+		active        = {{orNull .Active}}
 
 		tf_vars = [
 			{{- range .TerraformVariables }}
@@ -732,6 +1291,7 @@ resource "coderd_template" "test" {
 
 type testAccTemplateVersionConfig struct {
 	Name               *string
+	NameTemplate       *string
 	Message            *string
 	Directory          *string
 	Active             *bool
@@ -743,6 +1303,19 @@ type testAccTemplateKeyValueConfig struct {
 	Value *string
 }
 
+func testAccCheckTemplateActiveVersion(ctx context.Context, client *codersdk.Client, templateID, versionID uuid.UUID) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		template, err := client.Template(ctx, templateID)
+		if err != nil {
+			return err
+		}
+		if template.ActiveVersionID != versionID {
+			return fmt.Errorf("expected active version %s, got %s", versionID, template.ActiveVersionID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckNumTemplateVersions(ctx context.Context, client *codersdk.Client, expected int) resource.TestCheckFunc {
 	return func(*terraform.State) error {
 		templates, err := client.Templates(ctx, codersdk.TemplateFilter{})
@@ -765,6 +1338,24 @@ func testAccCheckNumTemplateVersions(ctx context.Context, client *codersdk.Clien
 	}
 }
 
+func TestMigrateLegacyHash(t *testing.T) {
+	t.Parallel()
+	prev := []PreviousTemplateVersion{{ID: uuid.New(), Name: "legacy"}}
+	lv := LastVersionsByHash{"legacy-hash": prev}
+	err := migrateLegacyHash(lv, "new-hash", func() (string, error) {
+		return "legacy-hash", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, LastVersionsByHash{"new-hash": prev}, lv)
+
+	// The legacy hash isn't computed if the current hash is known
+	err = migrateLegacyHash(lv, "new-hash", func() (string, error) {
+		return "", fmt.Errorf("unexpected call")
+	})
+	require.NoError(t, err)
+	require.Equal(t, LastVersionsByHash{"new-hash": prev}, lv)
+}
+
 func TestReconcileVersionIDs(t *testing.T) {
 	aUUID := uuid.New()
 	bUUID := uuid.New()
@@ -996,3 +1587,280 @@ func TestReconcileVersionIDs(t *testing.T) {
 
 	}
 }
+
+func TestMergeVariableValues(t *testing.T) {
+	t.Parallel()
+	fileVars := []codersdk.VariableValue{
+		{Name: "region", Value: "us-east-1"},
+		{Name: "instance_type", Value: "t3.micro"},
+	}
+	tfVars := []Variable{
+		{Name: types.StringValue("instance_type"), Value: types.StringValue("t3.large")},
+		{Name: types.StringValue("api_key"), Value: types.StringValue("secret")},
+	}
+
+	merged := mergeVariableValues(fileVars, tfVars)
+	require.Equal(t, []codersdk.VariableValue{
+		{Name: "region", Value: "us-east-1"},
+		{Name: "instance_type", Value: "t3.large"},
+		{Name: "api_key", Value: "secret"},
+	}, merged)
+	// The discovered variables are not modified
+	require.Equal(t, "t3.micro", fileVars[1].Value)
+}
+
+func TestMergeProvisionerTags(t *testing.T) {
+	t.Parallel()
+	defaults := map[string]string{
+		"scope": "organization",
+		"owner": "",
+	}
+	tags := []Variable{
+		{Name: types.StringValue("scope"), Value: types.StringValue("user")},
+		{Name: types.StringValue("region"), Value: types.StringValue("eu")},
+	}
+
+	merged := mergeProvisionerTags(defaults, tags)
+	require.Equal(t, map[string]string{
+		"scope":  "user",
+		"owner":  "",
+		"region": "eu",
+	}, merged)
+	// The defaults are not modified
+	require.Equal(t, "organization", defaults["scope"])
+
+	require.Empty(t, mergeProvisionerTags(nil, nil))
+}
+
+func TestVersionMessageFingerprint(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		message string
+	}{
+		{name: "Empty", message: ""},
+		{name: "SingleLine", message: "Update the image"},
+		{name: "MultiLine", message: "Update the image\n\nAnd the agent\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			message, fingerprint := splitVersionMessage(withVersionFingerprint(c.message, "abc123"))
+			require.Equal(t, c.message, message)
+			require.Equal(t, "abc123", fingerprint)
+
+			message, fingerprint = splitVersionMessage(c.message)
+			require.Equal(t, c.message, message)
+			require.Empty(t, fingerprint)
+		})
+	}
+
+	// The prefix must be on its own line to be a fingerprint
+	message, fingerprint := splitVersionMessage("See " + versionFingerprintPrefix + "abc123")
+	require.Equal(t, "See "+versionFingerprintPrefix+"abc123", message)
+	require.Empty(t, fingerprint)
+}
+
+func TestMatchesRemoteVersion(t *testing.T) {
+	t.Parallel()
+	planned := func() *TemplateVersion {
+		return &TemplateVersion{
+			Name:          types.StringUnknown(),
+			Message:       types.StringValue("Update the image"),
+			DirectoryHash: types.StringValue("hash"),
+		}
+	}
+	remote := codersdk.TemplateVersion{
+		Name:    "quirky-goldberg",
+		Message: withVersionFingerprint("Update the image", versionFingerprint(planned(), nil)),
+	}
+
+	require.True(t, matchesRemoteVersion(remote, planned(), nil))
+
+	named := planned()
+	named.Name = types.StringValue("quirky-goldberg")
+	require.True(t, matchesRemoteVersion(remote, named, nil))
+	named.Name = types.StringValue("other")
+	require.False(t, matchesRemoteVersion(remote, named, nil))
+
+	changed := planned()
+	changed.DirectoryHash = types.StringValue("other")
+	require.False(t, matchesRemoteVersion(remote, changed, nil))
+
+	message := planned()
+	message.Message = types.StringValue("Other")
+	require.False(t, matchesRemoteVersion(remote, message, nil))
+
+	// Provisioner tags are part of the fingerprint
+	require.False(t, matchesRemoteVersion(remote, planned(), map[string]string{"scope": "user"}))
+
+	// Versions with variables are never reused
+	withVars := planned()
+	withVars.TerraformVariables = []Variable{{Name: types.StringValue("a"), Value: types.StringValue("b")}}
+	require.Empty(t, versionFingerprint(withVars, nil))
+
+	// Versions pushed without a fingerprint are never reused
+	remote.Message = "Update the image"
+	require.False(t, matchesRemoteVersion(remote, planned(), nil))
+}
+
+func TestDeleteWorkspace(t *testing.T) {
+	t.Parallel()
+	buildID := uuid.New()
+	cases := []struct {
+		name        string
+		startStatus codersdk.ProvisionerJobStatus
+		endStatus   codersdk.ProvisionerJobStatus
+		orphan      bool
+		err         string
+	}{
+		{name: "Succeeded", startStatus: codersdk.ProvisionerJobPending, endStatus: codersdk.ProvisionerJobSucceeded},
+		{name: "Failed", startStatus: codersdk.ProvisionerJobRunning, endStatus: codersdk.ProvisionerJobFailed, err: "did not succeed"},
+		{name: "FailedImmediately", startStatus: codersdk.ProvisionerJobFailed, err: "did not succeed"},
+		{name: "Orphan", startStatus: codersdk.ProvisionerJobPending, endStatus: codersdk.ProvisionerJobSucceeded, orphan: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := c.endStatus
+				if r.Method == http.MethodPost {
+					var req codersdk.CreateWorkspaceBuildRequest
+					err := json.NewDecoder(r.Body).Decode(&req)
+					if err != nil || req.Transition != codersdk.WorkspaceTransitionDelete || req.Orphan != c.orphan {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					status = c.startStatus
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id":  buildID,
+					"job": map[string]any{"status": status},
+				})
+			}))
+			defer srv.Close()
+			srvURL, err := url.Parse(srv.URL)
+			require.NoError(t, err)
+
+			err = deleteWorkspace(context.Background(), codersdk.New(srvURL), codersdk.Workspace{ID: uuid.New()}, c.orphan, time.Minute)
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestVersionsToArchive(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	day := 24 * time.Hour
+	// Versions created 0, 1, 2, 3 and 4 days ago
+	versions := make([]codersdk.TemplateVersion, 0, 5)
+	for i := 4; i >= 0; i-- {
+		versions = append(versions, codersdk.TemplateVersion{
+			ID:        uuid.New(),
+			CreatedAt: now.Add(-time.Duration(i) * day),
+		})
+	}
+	ageDays := func(ids []uuid.UUID) []int {
+		var out []int
+		for _, id := range ids {
+			for i, version := range versions {
+				if version.ID == id {
+					out = append(out, 4-i)
+				}
+			}
+		}
+		return out
+	}
+
+	cases := []struct {
+		Name      string
+		Retention VersionRetention
+		Retained  []int
+		Archived  []int
+		Expected  []int
+	}{
+		{
+			Name:      "KeepLast",
+			Retention: VersionRetention{KeepLast: types.Int64Value(2), MaxAgeMillis: types.Int64Null()},
+			Expected:  []int{2, 3, 4},
+		},
+		{
+			Name:      "MaxAge",
+			Retention: VersionRetention{KeepLast: types.Int64Null(), MaxAgeMillis: types.Int64Value((3*day - time.Hour).Milliseconds())},
+			Expected:  []int{3, 4},
+		},
+		{
+			Name:      "BothLimits",
+			Retention: VersionRetention{KeepLast: types.Int64Value(1), MaxAgeMillis: types.Int64Value((2*day - time.Hour).Milliseconds())},
+			Expected:  []int{2, 3, 4},
+		},
+		{
+			Name:      "RetainedVersionsCount",
+			Retention: VersionRetention{KeepLast: types.Int64Value(2), MaxAgeMillis: types.Int64Null()},
+			Retained:  []int{0, 4},
+			Expected:  []int{2, 3},
+		},
+		{
+			Name:      "AlreadyArchived",
+			Retention: VersionRetention{KeepLast: types.Int64Value(2), MaxAgeMillis: types.Int64Null()},
+			Archived:  []int{3},
+			Expected:  []int{2, 4},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			t.Parallel()
+			input := slices.Clone(versions)
+			retained := make(map[uuid.UUID]struct{})
+			for _, age := range c.Retained {
+				retained[input[4-age].ID] = struct{}{}
+			}
+			for _, age := range c.Archived {
+				input[4-age].Archived = true
+			}
+			require.Equal(t, c.Expected, ageDays(versionsToArchive(input, retained, c.Retention, now)))
+		})
+	}
+}
+
+func TestFilterTemplateACL(t *testing.T) {
+	t.Parallel()
+	managedUser, otherUser := uuid.New(), uuid.New()
+	managedGroup, otherGroup := uuid.New(), uuid.New()
+	acl := codersdk.TemplateACL{
+		Users: []codersdk.TemplateUser{
+			{User: codersdk.User{ReducedUser: codersdk.ReducedUser{MinimalUser: codersdk.MinimalUser{ID: managedUser}}}, Role: codersdk.TemplateRoleAdmin},
+			{User: codersdk.User{ReducedUser: codersdk.ReducedUser{MinimalUser: codersdk.MinimalUser{ID: otherUser}}}, Role: codersdk.TemplateRoleUse},
+		},
+		Groups: []codersdk.TemplateGroup{
+			{Group: codersdk.Group{ID: managedGroup}, Role: codersdk.TemplateRoleUse},
+			{Group: codersdk.Group{ID: otherGroup}, Role: codersdk.TemplateRoleAdmin},
+		},
+	}
+	managed := ACL{
+		UserPermissions: []Permission{
+			{ID: types.StringValue(managedUser.String()), Role: types.StringValue("use")},
+		},
+		GroupPermissions: []Permission{
+			{ID: types.StringValue(managedGroup.String()), Role: types.StringValue("use")},
+			// Not in the ACL, e.g. removed outside of Terraform
+			{ID: types.StringValue(uuid.NewString()), Role: types.StringValue("use")},
+		},
+	}
+
+	filtered := filterTemplateACL(acl, managed)
+	require.Len(t, filtered.Users, 1)
+	require.Equal(t, managedUser, filtered.Users[0].ID)
+	// The role on the server is preserved, so drift is detected
+	require.Equal(t, codersdk.TemplateRoleAdmin, filtered.Users[0].Role)
+	require.Len(t, filtered.Groups, 1)
+	require.Equal(t, managedGroup, filtered.Groups[0].ID)
+
+	require.Empty(t, filterTemplateACL(acl, ACL{}).Users)
+}
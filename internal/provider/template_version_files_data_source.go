@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TemplateVersionFilesDataSource{}
+
+func NewTemplateVersionFilesDataSource() datasource.DataSource {
+	return &TemplateVersionFilesDataSource{}
+}
+
+// TemplateVersionFilesDataSource defines the data source implementation.
+type TemplateVersionFilesDataSource struct {
+	data *CoderdProviderData
+}
+
+// TemplateVersionFilesDataSourceModel describes the data source data model.
+type TemplateVersionFilesDataSourceModel struct {
+	TemplateVersionID UUID         `tfsdk:"template_version_id"`
+	OutputDirectory   types.String `tfsdk:"output_directory"`
+
+	Files types.Map `tfsdk:"files"`
+}
+
+func (d *TemplateVersionFilesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template_version_files"
+}
+
+func (d *TemplateVersionFilesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The source files of an existing template version, such as to fork a template into a new repository.\n\n" +
+			"The files are downloaded from the Coder deployment each time the data source is read.",
+
+		Attributes: map[string]schema.Attribute{
+			"template_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template version to retrieve the files of.",
+				CustomType:          UUIDType,
+				Required:            true,
+			},
+			"output_directory": schema.StringAttribute{
+				MarkdownDescription: "A directory to write the files to, which is created if it doesn't exist. Existing files with the same paths are overwritten.",
+				Optional:            true,
+			},
+			"files": schema.MapAttribute{
+				MarkdownDescription: "A map of the path of each file, relative to the root of the template, to its contents. " +
+					"Files that aren't valid UTF-8, such as images, are omitted, but are still written to `output_directory`.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *TemplateVersionFilesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *TemplateVersionFilesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TemplateVersionFilesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	version, err := client.TemplateVersion(ctx, data.TemplateVersionID.ValueUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get template version, got error: %s", err))
+		return
+	}
+	content, contentType, err := client.Download(ctx, version.Job.FileID)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to download template version files, got error: %s", err))
+		return
+	}
+
+	tmpDir, err := os.MkdirTemp("", "coderd-template-version-files-")
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to create temporary directory: %s", err))
+		return
+	}
+	defer os.RemoveAll(tmpDir)
+	archivePath := filepath.Join(tmpDir, "source")
+	err = os.WriteFile(archivePath, content, 0o600)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to write template version files: %s", err))
+		return
+	}
+	extract := func(dir string) error {
+		if contentType == codersdk.ContentTypeZip {
+			return extractZip(archivePath, dir)
+		}
+		return extractTar(archivePath, dir, false)
+	}
+
+	filesDir := filepath.Join(tmpDir, "files")
+	err = extract(filesDir)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to extract template version files: %s", err))
+		return
+	}
+	files, err := readTextFiles(filesDir)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to read template version files: %s", err))
+		return
+	}
+	filesMap, diags := types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Files = filesMap
+
+	if !data.OutputDirectory.IsNull() {
+		err = extract(data.OutputDirectory.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to write template version files to %s: %s", data.OutputDirectory.ValueString(), err))
+			return
+		}
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// readTextFiles reads the UTF-8 files in a directory, keyed by their
+// slash-separated path relative to the directory.
+func readTextFiles(dir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(content) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccTemplateVersionFilesDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "template_version_files_data_acc", false)
+
+	mainTF, err := os.ReadFile("../../integration/template-test/example-template/main.tf")
+	require.NoError(t, err)
+
+	cfg := testAccTemplateVersionFilesDataSourceConfig{
+		URL:             client.URL.String(),
+		Token:           client.SessionToken(),
+		Directory:       "../../integration/template-test/example-template",
+		OutputDirectory: filepath.Join(t.TempDir(), "fork"),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.coderd_template_version_files.test", "template_version_id", "coderd_template.test", "versions.0.id"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_files.test", "files.main.tf", string(mainTF)),
+					func(*terraform.State) error {
+						written, err := os.ReadFile(filepath.Join(cfg.OutputDirectory, "main.tf"))
+						if err != nil {
+							return err
+						}
+						require.Equal(t, string(mainTF), string(written))
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+type testAccTemplateVersionFilesDataSourceConfig struct {
+	URL   string
+	Token string
+
+	Directory       string
+	OutputDirectory string
+}
+
+func (c testAccTemplateVersionFilesDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+resource "coderd_template" "test" {
+	name = "example-template"
+	versions = [{
+		directory = "{{.Directory}}"
+		active    = true
+	}]
+}
+
+data "coderd_template_version_files" "test" {
+	template_version_id = coderd_template.test.versions[0].id
+	output_directory    = "{{.OutputDirectory}}"
+}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("templateVersionFilesDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TemplateVersionPresetsDataSource{}
+
+func NewTemplateVersionPresetsDataSource() datasource.DataSource {
+	return &TemplateVersionPresetsDataSource{}
+}
+
+// TemplateVersionPresetsDataSource defines the data source implementation.
+type TemplateVersionPresetsDataSource struct {
+	data *CoderdProviderData
+}
+
+// TemplateVersionPresetsDataSourceModel describes the data source data model.
+type TemplateVersionPresetsDataSourceModel struct {
+	TemplateVersionID UUID `tfsdk:"template_version_id"`
+
+	Presets []Preset `tfsdk:"presets"`
+}
+
+type Preset struct {
+	ID                       UUID         `tfsdk:"id"`
+	Name                     types.String `tfsdk:"name"`
+	Default                  types.Bool   `tfsdk:"default"`
+	DesiredPrebuildInstances types.Int64  `tfsdk:"desired_prebuild_instances"`
+	Parameters               types.Map    `tfsdk:"parameters"`
+}
+
+// presetResponse mirrors the preset type returned by the Coder API. Presets
+// were added after the version of codersdk this provider is built against.
+type presetResponse struct {
+	ID                       uuid.UUID
+	Name                     string
+	Default                  bool
+	DesiredPrebuildInstances *int
+	Parameters               []struct {
+		Name  string
+		Value string
+	}
+}
+
+func (d *TemplateVersionPresetsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template_version_presets"
+}
+
+func (d *TemplateVersionPresetsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `The parameter presets defined by an existing template version.
+
+~> **Warning**
+This data source is only compatible with Coder version [2.20.0](https://github.com/coder/coder/releases/tag/v2.20.0) and later.
+`,
+
+		Attributes: map[string]schema.Attribute{
+			"template_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template version to retrieve presets for.",
+				CustomType:          UUIDType,
+				Required:            true,
+			},
+			"presets": schema.ListNestedAttribute{
+				MarkdownDescription: "The presets defined by the template version.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the preset.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the preset.",
+							Computed:            true,
+						},
+						"default": schema.BoolAttribute{
+							MarkdownDescription: "Whether the preset is selected by default when creating a workspace.",
+							Computed:            true,
+						},
+						"desired_prebuild_instances": schema.Int64Attribute{
+							MarkdownDescription: "The number of prebuilt workspaces to maintain for the preset. Null if prebuilds are not configured.",
+							Computed:            true,
+						},
+						"parameters": schema.MapAttribute{
+							MarkdownDescription: "The parameter values set by the preset, keyed by parameter name.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TemplateVersionPresetsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *TemplateVersionPresetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TemplateVersionPresetsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.data.requireServerVersion("coderd_template_version_presets", minVersionTemplateVersionPresets)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	presets, err := templateVersionPresets(ctx, client, data.TemplateVersionID.ValueUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get template version presets, got error: %s", err))
+		return
+	}
+
+	tfPresets := make([]Preset, 0, len(presets))
+	for _, preset := range presets {
+		params := make(map[string]string, len(preset.Parameters))
+		for _, param := range preset.Parameters {
+			params[param.Name] = param.Value
+		}
+		paramsMap, diag := types.MapValueFrom(ctx, types.StringType, params)
+		resp.Diagnostics.Append(diag...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		prebuilds := types.Int64Null()
+		if preset.DesiredPrebuildInstances != nil {
+			prebuilds = types.Int64Value(int64(*preset.DesiredPrebuildInstances))
+		}
+		tfPresets = append(tfPresets, Preset{
+			ID:                       UUIDValue(preset.ID),
+			Name:                     types.StringValue(preset.Name),
+			Default:                  types.BoolValue(preset.Default),
+			DesiredPrebuildInstances: prebuilds,
+			Parameters:               paramsMap,
+		})
+	}
+	data.Presets = tfPresets
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func templateVersionPresets(ctx context.Context, client *codersdk.Client, versionID uuid.UUID) ([]presetResponse, error) {
+	res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templateversions/%s/presets", versionID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	var presets []presetResponse
+	return presets, json.NewDecoder(res.Body).Decode(&presets)
+}
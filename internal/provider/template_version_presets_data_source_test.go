@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccTemplateVersionPresetsDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "template_version_presets_data_acc", false)
+	buildInfo, err := client.BuildInfo(ctx)
+	require.NoError(t, err)
+	if err := checkServerVersion(buildInfo.Version, minVersionTemplateVersionPresets); err != nil {
+		t.Skipf("Template version presets are unsupported: %s", err)
+	}
+
+	cfg := testAccTemplateVersionPresetsDataSourceConfig{
+		URL:       client.URL.String(),
+		Token:     client.SessionToken(),
+		Directory: "../../integration/template-test/example-template",
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.coderd_template_version_presets.test", "template_version_id", "coderd_template.test", "versions.0.id"),
+					// The example template does not define any presets.
+					resource.TestCheckResourceAttr("data.coderd_template_version_presets.test", "presets.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+type testAccTemplateVersionPresetsDataSourceConfig struct {
+	URL   string
+	Token string
+
+	Directory string
+}
+
+func (c testAccTemplateVersionPresetsDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+resource "coderd_template" "test" {
+	name = "example-template"
+	versions = [{
+		directory = "{{.Directory}}"
+		active    = true
+	}]
+}
+
+data "coderd_template_version_presets" "test" {
+	template_version_id = coderd_template.test.versions[0].id
+}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("templateVersionPresetsDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &TemplateVersionVariablesDataSource{}
+
+func NewTemplateVersionVariablesDataSource() datasource.DataSource {
+	return &TemplateVersionVariablesDataSource{}
+}
+
+// TemplateVersionVariablesDataSource defines the data source implementation.
+type TemplateVersionVariablesDataSource struct {
+	data *CoderdProviderData
+}
+
+// TemplateVersionVariablesDataSourceModel describes the data source data model.
+type TemplateVersionVariablesDataSourceModel struct {
+	TemplateVersionID UUID `tfsdk:"template_version_id"`
+
+	Variables []TemplateVersionVariable `tfsdk:"variables"`
+}
+
+type TemplateVersionVariable struct {
+	Name         types.String `tfsdk:"name"`
+	Description  types.String `tfsdk:"description"`
+	Type         types.String `tfsdk:"type"`
+	Value        types.String `tfsdk:"value"`
+	DefaultValue types.String `tfsdk:"default_value"`
+	Required     types.Bool   `tfsdk:"required"`
+	Sensitive    types.Bool   `tfsdk:"sensitive"`
+}
+
+func (d *TemplateVersionVariablesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_template_version_variables"
+}
+
+func (d *TemplateVersionVariablesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The Terraform variables declared by an existing template version.\n\n" +
+			"The values of sensitive variables are redacted by the Coder deployment.",
+
+		Attributes: map[string]schema.Attribute{
+			"template_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the template version to retrieve variables for.",
+				CustomType:          UUIDType,
+				Required:            true,
+			},
+			"variables": schema.ListNestedAttribute{
+				MarkdownDescription: "The Terraform variables of the template version.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the variable.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "The description of the variable.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of the variable. Either `string`, `number` or `bool`.",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value the template version was built with.",
+							Computed:            true,
+						},
+						"default_value": schema.StringAttribute{
+							MarkdownDescription: "The default value of the variable.",
+							Computed:            true,
+						},
+						"required": schema.BoolAttribute{
+							MarkdownDescription: "Whether the variable has no default, and must be supplied when creating a template version.",
+							Computed:            true,
+						},
+						"sensitive": schema.BoolAttribute{
+							MarkdownDescription: "Whether the variable is sensitive.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *TemplateVersionVariablesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *TemplateVersionVariablesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data TemplateVersionVariablesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	variables, err := client.TemplateVersionVariables(ctx, data.TemplateVersionID.ValueUUID())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get template version variables, got error: %s", err))
+		return
+	}
+
+	tfVariables := make([]TemplateVersionVariable, 0, len(variables))
+	for _, variable := range variables {
+		tfVariables = append(tfVariables, TemplateVersionVariable{
+			Name:         types.StringValue(variable.Name),
+			Description:  types.StringValue(variable.Description),
+			Type:         types.StringValue(variable.Type),
+			Value:        types.StringValue(variable.Value),
+			DefaultValue: types.StringValue(variable.DefaultValue),
+			Required:     types.BoolValue(variable.Required),
+			Sensitive:    types.BoolValue(variable.Sensitive),
+		})
+	}
+	data.Variables = tfVariables
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
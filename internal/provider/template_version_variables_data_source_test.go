@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccTemplateVersionVariablesDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "template_version_variables_data_acc", false)
+
+	cfg := testAccTemplateVersionVariablesDataSourceConfig{
+		URL:       client.URL.String(),
+		Token:     client.SessionToken(),
+		Directory: "../../integration/template-test/example-template",
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.coderd_template_version_variables.test", "template_version_id", "coderd_template.test", "versions.0.id"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.#", "1"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.0.name", "name"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.0.type", "string"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.0.value", "world"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.0.required", "true"),
+					resource.TestCheckResourceAttr("data.coderd_template_version_variables.test", "variables.0.sensitive", "false"),
+				),
+			},
+		},
+	})
+}
+
+type testAccTemplateVersionVariablesDataSourceConfig struct {
+	URL   string
+	Token string
+
+	Directory string
+}
+
+func (c testAccTemplateVersionVariablesDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+resource "coderd_template" "test" {
+	name = "example-template"
+	versions = [{
+		directory = "{{.Directory}}"
+		active    = true
+	}]
+}
+
+data "coderd_template_version_variables" "test" {
+	template_version_id = coderd_template.test.versions[0].id
+}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("templateVersionVariablesDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
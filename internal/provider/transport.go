@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/time/rate"
+)
+
+// transportConfig configures the HTTP transport used to communicate with the
+// deployment.
+type transportConfig struct {
+	ClientCertFile string
+	ClientKeyFile  string
+	// CACertificate is PEM-encoded certificates to trust in addition to the
+	// system trust store.
+	CACertificate     string
+	CACertificateFile string
+	// InsecureSkipVerify disables verification of the deployment's
+	// certificate.
+	InsecureSkipVerify bool
+	// HTTPProxy is the URL of an HTTP, HTTPS or SOCKS5 proxy to use instead
+	// of the proxy from the environment. NoProxy is a comma-separated list of
+	// hosts to connect to directly.
+	HTTPProxy string
+	NoProxy   string
+}
+
+func newTransport(cfg transportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CACertificate != "" || cfg.CACertificateFile != "" {
+		pem := []byte(cfg.CACertificate)
+		if cfg.CACertificateFile != "" {
+			var err error
+			pem, err = os.ReadFile(cfg.CACertificateFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM-encoded CA certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	tlsConfig.InsecureSkipVerify = cfg.InsecureSkipVerify
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.HTTPProxy != "" {
+		proxyURL, err := url.Parse(cfg.HTTPProxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.HTTPProxy)
+		}
+		proxyFunc := (&httpproxy.Config{
+			HTTPProxy:  cfg.HTTPProxy,
+			HTTPSProxy: cfg.HTTPProxy,
+			NoProxy:    cfg.NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+	return transport, nil
+}
+
+// retryTransport retries requests that fail transiently, such as while the
+// deployment is restarting, with exponential backoff and jitter.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	minDelay   time.Duration
+	maxDelay   time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		res, err := t.base.RoundTrip(req)
+		if attempt >= t.maxRetries || !shouldRetry(req, res, err) {
+			return res, err
+		}
+		status := 0
+		delay := t.backoff(attempt)
+		if res != nil {
+			status = res.StatusCode
+			if after, ok := retryAfter(res, time.Now()); ok {
+				delay = min(after, t.maxDelay)
+			}
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		tflog.Debug(ctx, "retrying request", map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"status":  status,
+			"error":   fmt.Sprint(err),
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the given retry, doubling from minDelay up
+// to maxDelay, with jitter of up to half the delay.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.maxDelay
+	if attempt < 32 && t.minDelay<<attempt > 0 && t.minDelay<<attempt < t.maxDelay {
+		delay = t.minDelay << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// shouldRetry returns whether a request failed transiently. Requests that
+// may have been processed by the deployment are only retried if they are
+// idempotent.
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	// The body can't be sent again.
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return true
+		}
+		return isIdempotent(req.Method)
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The request was rejected without being processed.
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		// The deployment may have processed the request before the proxy
+		// in front of it failed.
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the Retry-After header of a 429
+// or 503 response, which is either a number of seconds or a date.
+func retryAfter(res *http.Response, now time.Time) (time.Duration, bool) {
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// rateLimitTransport limits the rate of requests, so large applies don't
+// overwhelm the deployment.
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// tokenCommand is an external program, and its arguments, that prints an API
+// token.
+type tokenCommand []string
+
+func (c tokenCommand) token(ctx context.Context) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c[0], c[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token command printed an empty token")
+	}
+	return token, nil
+}
+
+// tokenRefreshTransport runs the token command again when a request is
+// unauthorized, such as when the token has expired, and retries the request
+// with the new token.
+type tokenRefreshTransport struct {
+	base    http.RoundTripper
+	command tokenCommand
+	// setToken is called with each new token.
+	setToken func(token string)
+
+	mu    sync.Mutex
+	token string
+}
+
+func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	// The body can't be sent again.
+	if req.Body != nil && req.GetBody == nil {
+		return res, nil
+	}
+
+	t.mu.Lock()
+	// Another request may have refreshed the token already.
+	if req.Header.Get(codersdk.SessionTokenHeader) == t.token {
+		token, err := t.command.token(req.Context())
+		if err != nil {
+			t.mu.Unlock()
+			tflog.Warn(req.Context(), "failed to refresh token", map[string]any{
+				"error": err.Error(),
+			})
+			return res, nil
+		}
+		t.token = token
+		t.setToken(token)
+	}
+	token := t.token
+	t.mu.Unlock()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	retry := req.Clone(req.Context())
+	retry.Header.Set(codersdk.SessionTokenHeader, token)
+	if req.Body != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(retry)
+}
+
+// timeoutTransport limits the time taken by each request, including reading
+// the response body. Upgraded connections, such as for streaming logs, aren't
+// limited.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Upgrade") != "" {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	res, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// cancelOnClose cancels the context of a request once its response body is
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// concurrencyTransport limits the number of requests in flight, including
+// reading their responses, so the deployment isn't overloaded by parallel
+// applies. Upgraded connections, which stream logs, aren't limited.
+type concurrencyTransport struct {
+	base  http.RoundTripper
+	slots chan struct{}
+}
+
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Upgrade") != "" {
+		return t.base.RoundTrip(req)
+	}
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		<-t.slots
+		return nil, err
+	}
+	var once sync.Once
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: func() {
+		once.Do(func() { <-t.slots })
+	}}
+	return res, nil
+}
+
+// headerTransport adds headers to each request.
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
@@ -28,12 +29,12 @@ type UserDataSource struct {
 
 // UserDataSourceModel describes the data source data model.
 type UserDataSourceModel struct {
-	// Username or ID must be set
+	// Username, ID or email must be set
 	ID       UUID         `tfsdk:"id"`
 	Username types.String `tfsdk:"username"`
+	Email    types.String `tfsdk:"email"`
 
 	Name            types.String `tfsdk:"name"`
-	Email           types.String `tfsdk:"email"`
 	Roles           types.Set    `tfsdk:"roles"`      // owner, template-admin, user-admin, auditor (member is implicit)
 	LoginType       types.String `tfsdk:"login_type"` // none, password, github, oidc
 	Suspended       types.Bool   `tfsdk:"suspended"`
@@ -56,19 +57,20 @@ func (d *UserDataSource) Schema(ctx context.Context, req datasource.SchemaReques
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				CustomType:          UUIDType,
-				MarkdownDescription: "The ID of the user to retrieve. This field will be populated if a username is supplied.",
+				MarkdownDescription: "The ID of the user to retrieve. This field will be populated if a username or email is supplied.",
 				Optional:            true,
 			},
 			"username": schema.StringAttribute{
-				MarkdownDescription: "The username of the user to retrieve. This field will be populated if an ID is supplied.",
+				MarkdownDescription: "The username of the user to retrieve. This field will be populated if an ID or email is supplied.",
 				Optional:            true,
 			},
-			"name": schema.StringAttribute{
-				MarkdownDescription: "Display name of the user.",
+			"email": schema.StringAttribute{
+				MarkdownDescription: "The email of the user to retrieve, matched case-insensitively. This field will be populated if an ID or username is supplied.",
+				Optional:            true,
 				Computed:            true,
 			},
-			"email": schema.StringAttribute{
-				MarkdownDescription: "Email of the user.",
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Display name of the user.",
 				Computed:            true,
 			},
 			"roles": schema.SetAttribute{
@@ -144,8 +146,19 @@ func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	var ident string
 	if !data.ID.IsNull() {
 		ident = data.ID.ValueString()
-	} else {
+	} else if !data.Username.IsNull() {
 		ident = data.Username.ValueString()
+	} else {
+		found, ok, err := userByEmail(ctx, client, data.Email.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find user by email, got error: %s", err))
+			return
+		}
+		if !ok {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("No user with email %q exists", data.Email.ValueString()))
+			return
+		}
+		ident = found.ID.String()
 	}
 	user, err := client.User(ctx, ident)
 	if err != nil {
@@ -162,12 +175,18 @@ func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	} else if !data.Username.IsNull() && user.Username != data.Username.ValueString() {
 		resp.Diagnostics.AddError("Client Error", "Retrieved User's username does not match the provided username")
 		return
+	} else if !data.Email.IsNull() && !strings.EqualFold(user.Email, data.Email.ValueString()) {
+		resp.Diagnostics.AddError("Client Error", "Retrieved User's email does not match the provided email")
+		return
 	}
 
 	data.ID = UUIDValue(user.ID)
 	data.Username = types.StringValue(user.Username)
 	data.Name = types.StringValue(user.Name)
-	data.Email = types.StringValue(user.Email)
+	// Preserve the configured casing of the email
+	if data.Email.IsNull() {
+		data.Email = types.StringValue(user.Email)
+	}
 	roles := make([]attr.Value, 0, len(user.Roles))
 	for _, role := range user.Roles {
 		roles = append(roles, types.StringValue(role.Name))
@@ -194,6 +213,7 @@ func (d *UserDataSource) ConfigValidators(context.Context) []datasource.ConfigVa
 		datasourcevalidator.AtLeastOneOf(
 			path.MatchRoot("id"),
 			path.MatchRoot("username"),
+			path.MatchRoot("email"),
 		),
 	}
 }
@@ -85,6 +85,44 @@ func TestAccUserDataSource(t *testing.T) {
 			},
 		})
 	})
+	t.Run("UserByEmailOk", func(t *testing.T) {
+		cfg := testAccUserDataSourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			Email: PtrTo("Example@Coder.com"),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: cfg.String(t),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.coderd_user.test", "id", user.ID.String()),
+						resource.TestCheckResourceAttr("data.coderd_user.test", "username", "example"),
+					),
+				},
+			},
+		})
+	})
+	t.Run("UserByEmailNotFoundError", func(t *testing.T) {
+		cfg := testAccUserDataSourceConfig{
+			URL:   client.URL.String(),
+			Token: client.SessionToken(),
+			// Matches the fuzzy search, but not exactly
+			Email: PtrTo("example@coder"),
+		}
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config:      cfg.String(t),
+					ExpectError: regexp.MustCompile(`no user with email "example@coder"`),
+				},
+			},
+		})
+	})
 	t.Run("NeitherIDNorUsernameError", func(t *testing.T) {
 		cfg := testAccUserDataSourceConfig{
 			URL:   client.URL.String(),
@@ -97,7 +135,7 @@ func TestAccUserDataSource(t *testing.T) {
 			Steps: []resource.TestStep{
 				{
 					Config:      cfg.String(t),
-					ExpectError: regexp.MustCompile(`At least one of these attributes must be configured: \[id,username\]`),
+					ExpectError: regexp.MustCompile(`At least one of these attributes must be configured: \[id,username,email\]`),
 				},
 			},
 		})
@@ -111,6 +149,7 @@ type testAccUserDataSourceConfig struct {
 
 	ID       *string
 	Username *string
+	Email    *string
 }
 
 func (c testAccUserDataSourceConfig) String(t *testing.T) string {
@@ -124,6 +163,7 @@ provider coderd {
 data "coderd_user" "test" {
 	id       = {{orNull .ID}}
 	username = {{orNull .Username}}
+	email    = {{orNull .Email}}
 }`
 
 	funcMap := template.FuncMap{
@@ -2,19 +2,27 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -27,6 +35,8 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &UserResource{}
 var _ resource.ResourceWithImportState = &UserResource{}
+var _ resource.ResourceWithModifyPlan = &UserResource{}
+var _ resource.ResourceWithUpgradeState = &UserResource{}
 
 func NewUserResource() resource.Resource {
 	return &UserResource{}
@@ -37,27 +47,49 @@ type UserResource struct {
 	data *CoderdProviderData
 }
 
+// The policies for the workspaces of a user being deleted.
+const (
+	userOnDestroyFail             = "fail_if_workspaces"
+	userOnDestroyDeleteWorkspaces = "delete_workspaces"
+	userOnDestroyOrphan           = "orphan"
+)
+
 // UserResourceModel describes the resource data model.
 type UserResourceModel struct {
 	ID UUID `tfsdk:"id"`
 
-	Username  types.String `tfsdk:"username"`
-	Name      types.String `tfsdk:"name"`
-	Email     types.String `tfsdk:"email"`
-	Roles     types.Set    `tfsdk:"roles"`      // owner, template-admin, user-admin, auditor (member is implicit)
-	LoginType types.String `tfsdk:"login_type"` // none, password, github, oidc
-	Password  types.String `tfsdk:"password"`   // only when login_type is password
-	Suspended types.Bool   `tfsdk:"suspended"`
+	Username  CaseInsensitiveString `tfsdk:"username"`
+	Name      types.String          `tfsdk:"name"`
+	Email     CaseInsensitiveString `tfsdk:"email"`
+	Roles     types.Set             `tfsdk:"roles"`      // owner, template-admin, user-admin, auditor (member is implicit)
+	LoginType types.String          `tfsdk:"login_type"` // none, password, github, oidc
+	Password  types.String          `tfsdk:"password"`   // only when login_type is password
+	Suspended types.Bool            `tfsdk:"suspended"`
+
+	Organizations     types.Set `tfsdk:"organizations"`
+	OrganizationRoles types.Map `tfsdk:"organization_roles"`
+
+	ThemePreference types.String `tfsdk:"theme_preference"`
+	TerminalFont    types.String `tfsdk:"terminal_font"`
+
+	AdoptExisting types.Bool   `tfsdk:"adopt_existing"`
+	OnDestroy     types.String `tfsdk:"on_destroy"`
+
+	OneTimePasscodeTrigger types.String `tfsdk:"one_time_passcode_trigger"`
 }
 
+var organizationRolesType = types.SetType{ElemType: types.StringType}
+
 func (r *UserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_user"
 }
 
 func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: schemaVersion,
 		MarkdownDescription: "A user on the Coder deployment.\n\n" +
-			"When importing, the ID supplied can be either a user UUID or a username.",
+			"When importing, the ID supplied can be either a user UUID, a username or an email. " +
+			"The user's organization roles and terminal font are imported as managed.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -69,7 +101,8 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"username": schema.StringAttribute{
-				MarkdownDescription: "Username of the user.",
+				MarkdownDescription: "Username of the user. Usernames are case-insensitive, so differences in case from the username in Coder aren't drift.",
+				CustomType:          CaseInsensitiveStringType,
 				Required:            true,
 				Validators: []validator.String{
 					stringvalidator.LengthBetween(1, 32),
@@ -85,7 +118,8 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"email": schema.StringAttribute{
-				MarkdownDescription: "Email address of the user.",
+				MarkdownDescription: "Email address of the user. Emails are case-insensitive, so differences in case from the email in Coder aren't drift.",
+				CustomType:          CaseInsensitiveStringType,
 				Required:            true,
 			},
 			"roles": schema.SetAttribute{
@@ -101,28 +135,87 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				Default: setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
 			},
 			"login_type": schema.StringAttribute{
-				MarkdownDescription: "Type of login for the user. Valid types are `none`, `password`, `github`, and `oidc`.",
-				Computed:            true,
-				Optional:            true,
+				MarkdownDescription: "Type of login for the user. Valid types are `none`, `password`, `github`, and `oidc`. " +
+					"Changing the login type converts the existing user, rather than replacing it. Converting to `password` requires `password` to be set.",
+				Computed: true,
+				Optional: true,
 				Validators: []validator.String{
 					stringvalidator.OneOf("none", "password", "github", "oidc"),
 				},
 				Default: stringdefault.StaticString("none"),
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplaceIfConfigured(),
-				},
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "Password for the user. Required when `login_type` is `password`. Passwords are saved into the state as plain text and should only be used for testing purposes.",
+				MarkdownDescription: "Password for the user. Required when `login_type` is `password`, unless `one_time_passcode_trigger` is set. Passwords are saved into the state as plain text, as write-only attributes require a newer version of the Terraform plugin framework than the provider is built with, and should only be used for testing purposes.",
 				Optional:            true,
 				Sensitive:           true,
 			},
 			"suspended": schema.BoolAttribute{
-				MarkdownDescription: "Whether the user is suspended.",
+				MarkdownDescription: "Whether the user is suspended. Suspended users can't log in or use their workspaces, but their account and workspaces are kept, and are available again once the user is reactivated.",
 				Computed:            true,
 				Optional:            true,
 				Default:             booldefault.StaticBool(false),
 			},
+			"theme_preference": schema.StringAttribute{
+				MarkdownDescription: "The user's theme preference in the Coder dashboard, such as `auto`, `dark` or `light`. Defaults to the user's current preference.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"terminal_font": schema.StringAttribute{
+				MarkdownDescription: "The font used by the terminal in the Coder dashboard, such as `ibm-plex-mono`, `fira-code`, `source-code-pro` or `jetbrains-mono`. " +
+					"If `null`, the terminal font will not be managed by Terraform. Requires a Coder deployment that supports terminal font preferences.",
+				Optional: true,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to manage an existing user with the same username or email, such as one created by OIDC just-in-time provisioning, instead of failing to create the user. " +
+					"The existing user is updated to match the configuration, including converting its login type. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"on_destroy": schema.StringAttribute{
+				MarkdownDescription: "What to do with the user's workspaces when the user is deleted, as Coder can't delete users that own workspaces. " +
+					"`fail_if_workspaces` fails to delete the user, listing the workspaces. " +
+					"`delete_workspaces` destroys the workspaces with delete builds, which must succeed. " +
+					"`orphan` deletes the workspaces without destroying their resources. " +
+					"The value must be applied before it takes effect on destroy. Defaults to `fail_if_workspaces`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(userOnDestroyFail),
+				Validators: []validator.String{
+					stringvalidator.OneOf(userOnDestroyFail, userOnDestroyDeleteWorkspaces, userOnDestroyOrphan),
+				},
+			},
+			"one_time_passcode_trigger": schema.StringAttribute{
+				MarkdownDescription: "An arbitrary value that, when set or changed, emails the user a one-time passcode to reset their password, so credentials can be set up without storing a password in Terraform. " +
+					"Only allowed when `login_type` is `password`, and requires a Coder deployment with email notifications configured.",
+				Optional: true,
+			},
+			"organizations": schema.SetAttribute{
+				MarkdownDescription: "IDs of the organizations the user is a member of. Users are added to the default organization when created, " +
+					"and are added to or removed from organizations to match this set. If `null`, organization memberships will not be managed by Terraform. " +
+					"Multiple organizations require an Enterprise license.",
+				ElementType: UUIDType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"organization_roles": schema.MapAttribute{
+				MarkdownDescription: "Roles assigned to the user in each organization, keyed by organization ID, such as `organization-admin`. " +
+					"The user must be a member of each organization. The member role is implicit. " +
+					"If `null`, organization roles will not be managed by Terraform, and changing it to `null` leaves the user's roles as they are. Organizations removed from the map have the user's roles in them removed.",
+				Optional:    true,
+				ElementType: organizationRolesType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(
+						stringvalidator.RegexMatches(uuidRegex, "must be an organization ID"),
+					),
+				},
+			},
 		},
 	}
 }
@@ -147,6 +240,63 @@ func (r *UserResource) Configure(ctx context.Context, req resource.ConfigureRequ
 	r.data = data
 }
 
+// UpgradeState implements resource.ResourceWithUpgradeState.
+func (r *UserResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return stateUpgraders(ctx, r)
+}
+
+func (r *UserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var trigger types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("one_time_passcode_trigger"), &trigger)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !trigger.IsNull() {
+		resp.Diagnostics.Append(r.data.requireServerVersion("one_time_passcode_trigger", minVersionOneTimePasscode)...)
+	}
+	if r.data != nil {
+		resp.Diagnostics.Append(r.checkUsernameConflict(ctx, req)...)
+	}
+}
+
+// checkUsernameConflict returns an error if the user is being created or
+// renamed, and another user already has the username. Existing users are
+// adopted instead when creating with adopt_existing.
+func (r *UserResource) checkUsernameConflict(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	username, changed, diags := plannedChange[CaseInsensitiveString](ctx, req, path.Root("username"))
+	if !changed || diags.HasError() {
+		return diags
+	}
+	var current UUID
+	if req.State.Raw.IsNull() {
+		var adopt types.Bool
+		diags.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adopt)...)
+		if diags.HasError() || adopt.ValueBool() {
+			return diags
+		}
+	} else {
+		diags.Append(req.State.GetAttribute(ctx, path.Root("id"), &current)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	user, err := r.data.Client.User(ctx, username)
+	switch {
+	case isNotFound(err):
+	case err != nil:
+		diags.AddError("Client Error", fmt.Sprintf("Unable to check for user %q, got error: %s", username, err))
+	case !current.IsNull() && user.ID == current.ValueUUID():
+		// Usernames are case-insensitive, so only the case is changing.
+	default:
+		diags.AddAttributeError(path.Root("username"), "Username Conflict",
+			fmt.Sprintf("A user named %q already exists. Choose another username, import the existing user with `terraform import`, or set adopt_existing = true.", username))
+	}
+	return diags
+}
+
 func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data UserResourceModel
 
@@ -168,34 +318,101 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	tflog.Info(ctx, "creating user")
 	loginType := codersdk.LoginType(data.LoginType.ValueString())
-	if loginType == codersdk.LoginTypePassword && data.Password.IsNull() {
-		resp.Diagnostics.AddError("Data Error", "Password is required when login_type is 'password'")
+	if loginType == codersdk.LoginTypePassword && data.Password.IsNull() && data.OneTimePasscodeTrigger.IsNull() {
+		resp.Diagnostics.AddError("Data Error", "Password or one_time_passcode_trigger is required when login_type is 'password'")
 		return
 	}
 	if loginType != codersdk.LoginTypePassword && !data.Password.IsNull() {
 		resp.Diagnostics.AddError("Data Error", "Password is only allowed when login_type is 'password'")
 		return
 	}
-	user, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
-		Email:          data.Email.ValueString(),
-		Username:       data.Username.ValueString(),
-		Password:       data.Password.ValueString(),
-		UserLoginType:  loginType,
-		OrganizationID: me.OrganizationIDs[0],
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create user, got error: %s", err))
+	if loginType != codersdk.LoginTypePassword && !data.OneTimePasscodeTrigger.IsNull() {
+		resp.Diagnostics.AddError("Data Error", "One-time passcodes are only allowed when login_type is 'password'")
 		return
 	}
-	tflog.Info(ctx, "successfully created user", map[string]any{
-		"id": user.ID.String(),
-	})
+
+	var user codersdk.User
+	adopted := false
+	if data.AdoptExisting.ValueBool() {
+		user, adopted, err = findExistingUser(ctx, client, data.Username.ValueString(), data.Email.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to adopt existing user, got error: %s", err))
+			return
+		}
+	}
+	if adopted {
+		tflog.Info(ctx, "adopting existing user", map[string]any{
+			"id": user.ID.String(),
+		})
+		if user.LoginType != loginType {
+			err = updateUserLoginType(ctx, client, user.ID, loginType)
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to convert adopted user login type, got error: %s", err))
+				return
+			}
+		}
+		if loginType == codersdk.LoginTypePassword && !data.Password.IsNull() {
+			err = client.UpdateUserPassword(ctx, user.ID.String(), codersdk.UpdateUserPasswordRequest{
+				Password: data.Password.ValueString(),
+			})
+			if err != nil && !strings.Contains(err.Error(), "New password cannot match old password.") {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update adopted user password, got error: %s", err))
+				return
+			}
+		}
+		tflog.Info(ctx, "successfully adopted existing user")
+	} else {
+		password := data.Password.ValueString()
+		if loginType == codersdk.LoginTypePassword && data.Password.IsNull() {
+			// The user sets their password with a one-time passcode.
+			password, err = randomPassword()
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to generate password, got error: %s", err))
+				return
+			}
+		}
+		tflog.Info(ctx, "creating user")
+		user, err = createIdempotently(ctx, "user", func() (codersdk.User, error) {
+			return client.CreateUser(ctx, codersdk.CreateUserRequest{
+				Email:          data.Email.ValueString(),
+				Username:       data.Username.ValueString(),
+				Password:       password,
+				UserLoginType:  loginType,
+				OrganizationID: me.OrganizationIDs[0],
+			})
+		}, func(start time.Time, _ error) (codersdk.User, bool, error) {
+			user, err := client.User(ctx, data.Username.ValueString())
+			if isNotFound(err) {
+				return user, false, nil
+			}
+			ours := strings.EqualFold(user.Email, data.Email.ValueString()) && createdSince(user.CreatedAt, start)
+			return user, err == nil && ours, err
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create user, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully created user", map[string]any{
+			"id": user.ID.String(),
+		})
+	}
 	data.ID = UUIDValue(user.ID)
+	suspended := user.Status == codersdk.UserStatusSuspended
+	// Delete the user if a later step fails, so the next apply creates it
+	// again, or save it if it can't be deleted. Adopted users aren't saved,
+	// as replacing them would delete them, and the next apply adopts them
+	// again.
+	defer func() {
+		if resp.Diagnostics.HasError() && !adopted && !rollbackCreate(ctx, resp, "user", func(ctx context.Context) error {
+			return client.DeleteUser(ctx, user.ID)
+		}) {
+			savePartialCreate(ctx, resp, "user", &data)
+		}
+	}()
 
 	tflog.Info(ctx, "updating user profile")
-	name := data.Username
+	name := data.Username.StringValue
 	if data.Name.ValueString() != "" {
 		name = data.Name
 	}
@@ -226,13 +443,64 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 	tflog.Info(ctx, "successfully updated user roles")
 
-	if data.Suspended.ValueBool() {
-		_, err = client.UpdateUserStatus(ctx, data.ID.ValueString(), codersdk.UserStatus("suspended"))
+	addOrgs, removeOrgs, diags := organizationChanges(ctx, user, data.Organizations)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update user status, got error: %s", err))
+	resp.Diagnostics.Append(r.addToOrganizations(ctx, user.ID, addOrgs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.updateOrganizationRoles(ctx, user.ID, data.OrganizationRoles, types.MapNull(organizationRolesType))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.removeFromOrganizations(ctx, user.ID, removeOrgs)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	if data.Organizations.IsUnknown() {
+		data.Organizations = organizationIDsValue(user.OrganizationIDs)
+	}
+
+	if data.ThemePreference.IsUnknown() {
+		data.ThemePreference = types.StringValue(user.ThemePreference)
+	}
+	if data.ThemePreference.ValueString() != user.ThemePreference || !data.TerminalFont.IsNull() {
+		tflog.Info(ctx, "updating user appearance")
+		err = updateUserAppearance(ctx, client, user.ID, userAppearance{
+			ThemePreference: data.ThemePreference.ValueString(),
+			TerminalFont:    data.TerminalFont.ValueString(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update newly created user appearance, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully updated user appearance")
+	}
+
+	if data.Suspended.ValueBool() != suspended {
+		status := codersdk.UserStatusActive
+		if data.Suspended.ValueBool() {
+			status = codersdk.UserStatusSuspended
+		}
+		_, err = client.UpdateUserStatus(ctx, data.ID.ValueString(), status)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update user status, got error: %s", err))
+			return
+		}
+	}
+
+	if !data.OneTimePasscodeTrigger.IsNull() {
+		tflog.Info(ctx, "requesting one-time passcode")
+		err = requestOneTimePasscode(ctx, client, user.Email)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to request one-time passcode, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully requested one-time passcode")
+	}
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -250,6 +518,13 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	client := r.data.Client
 
 	user, err := client.User(ctx, data.ID.ValueString())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "user not found, removing from state", map[string]any{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get current user, got error: %s", err))
 		return
@@ -259,17 +534,45 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	data.Email = types.StringValue(user.Email)
+	data.Email = CaseInsensitiveStringValue(user.Email)
 	data.Name = types.StringValue(user.Name)
-	data.Username = types.StringValue(user.Username)
+	data.Username = CaseInsensitiveStringValue(user.Username)
 	roles := make([]attr.Value, 0, len(user.Roles))
 	for _, role := range user.Roles {
 		roles = append(roles, types.StringValue(role.Name))
 	}
 	data.Roles = types.SetValueMust(types.StringType, roles)
 	data.LoginType = types.StringValue(string(user.LoginType))
+	data.Organizations = organizationIDsValue(user.OrganizationIDs)
 	data.Suspended = types.BoolValue(user.Status == codersdk.UserStatusSuspended)
 
+	// Not set when importing
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+	// Not set when importing
+	if data.OnDestroy.IsNull() {
+		data.OnDestroy = types.StringValue(userOnDestroyFail)
+	}
+	data.ThemePreference = types.StringValue(user.ThemePreference)
+	if !data.TerminalFont.IsNull() {
+		appearance, err := userAppearanceSettings(ctx, client, user.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get user appearance, got error: %s", err))
+			return
+		}
+		data.TerminalFont = types.StringValue(appearance.TerminalFont)
+	}
+
+	if !data.OrganizationRoles.IsNull() {
+		orgRoles, diags := r.readOrganizationRoles(ctx, user.ID, data.OrganizationRoles)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		data.OrganizationRoles = orgRoles
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -284,6 +587,12 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	var prior UserResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	client := r.data.Client
 
 	user, err := client.User(ctx, data.ID.ValueString())
@@ -296,7 +605,7 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	name := data.Username
+	name := data.Username.StringValue
 	if data.Name.ValueString() != "" {
 		name = data.Name
 	}
@@ -315,6 +624,28 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	data.Name = name
 	tflog.Info(ctx, "successfully updated user profile")
 
+	if !data.LoginType.Equal(prior.LoginType) {
+		loginType := codersdk.LoginType(data.LoginType.ValueString())
+		if loginType == codersdk.LoginTypePassword && data.Password.IsNull() && data.OneTimePasscodeTrigger.IsNull() {
+			resp.Diagnostics.AddError("Data Error", "Password or one_time_passcode_trigger is required when login_type is 'password'")
+			return
+		}
+		if loginType != codersdk.LoginTypePassword && !data.Password.IsNull() {
+			resp.Diagnostics.AddError("Data Error", "Password is only allowed when login_type is 'password'")
+			return
+		}
+		tflog.Info(ctx, "converting user login type", map[string]any{
+			"old_login_type": prior.LoginType.ValueString(),
+			"new_login_type": loginType,
+		})
+		err = updateUserLoginType(ctx, client, user.ID, loginType)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to convert user login type, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully converted user login type")
+	}
+
 	var roles []string
 	resp.Diagnostics.Append(
 		data.Roles.ElementsAs(ctx, &roles, false)...,
@@ -331,6 +662,46 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 	tflog.Info(ctx, "successfully updated user roles")
 
+	addOrgs, removeOrgs, diags := organizationChanges(ctx, user, data.Organizations)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.addToOrganizations(ctx, user.ID, addOrgs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.updateOrganizationRoles(ctx, user.ID, data.OrganizationRoles, prior.OrganizationRoles)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.removeFromOrganizations(ctx, user.ID, removeOrgs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Organizations.IsUnknown() {
+		data.Organizations = organizationIDsValue(user.OrganizationIDs)
+	}
+
+	if data.ThemePreference.IsUnknown() {
+		data.ThemePreference = types.StringValue(user.ThemePreference)
+	}
+	if !data.ThemePreference.Equal(prior.ThemePreference) || !data.TerminalFont.Equal(prior.TerminalFont) {
+		tflog.Info(ctx, "updating user appearance", map[string]any{
+			"new_theme_preference": data.ThemePreference.ValueString(),
+			"new_terminal_font":    data.TerminalFont.ValueString(),
+		})
+		err = updateUserAppearance(ctx, client, user.ID, userAppearance{
+			ThemePreference: data.ThemePreference.ValueString(),
+			TerminalFont:    data.TerminalFont.ValueString(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update user appearance, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully updated user appearance")
+	}
+
 	if data.LoginType.ValueString() == string(codersdk.LoginTypePassword) && !data.Password.IsNull() {
 		tflog.Info(ctx, "updating password")
 		err = client.UpdateUserPassword(ctx, user.ID.String(), codersdk.UpdateUserPasswordRequest{
@@ -343,16 +714,35 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		tflog.Info(ctx, "successfully updated password")
 	}
 
-	var statusErr error
-	if data.Suspended.ValueBool() {
-		_, statusErr = client.UpdateUserStatus(ctx, data.ID.ValueString(), codersdk.UserStatus("suspended"))
-	}
-	if !data.Suspended.ValueBool() && user.Status == codersdk.UserStatusSuspended {
-		_, statusErr = client.UpdateUserStatus(ctx, data.ID.ValueString(), codersdk.UserStatus("active"))
+	if !data.OneTimePasscodeTrigger.IsNull() && !data.OneTimePasscodeTrigger.Equal(prior.OneTimePasscodeTrigger) {
+		if data.LoginType.ValueString() != string(codersdk.LoginTypePassword) {
+			resp.Diagnostics.AddError("Data Error", "One-time passcodes are only allowed when login_type is 'password'")
+			return
+		}
+		tflog.Info(ctx, "requesting one-time passcode")
+		err = requestOneTimePasscode(ctx, client, user.Email)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to request one-time passcode, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully requested one-time passcode")
 	}
-	if statusErr != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update user status, got error: %s", err))
-		return
+
+	suspended := user.Status == codersdk.UserStatusSuspended
+	if data.Suspended.ValueBool() != suspended {
+		status := codersdk.UserStatusActive
+		if data.Suspended.ValueBool() {
+			status = codersdk.UserStatusSuspended
+		}
+		tflog.Info(ctx, "updating user status", map[string]any{
+			"new_status": status,
+		})
+		_, err = client.UpdateUserStatus(ctx, data.ID.ValueString(), status)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update user status, got error: %s", err))
+			return
+		}
+		tflog.Info(ctx, "successfully updated user status")
 	}
 
 	// Save updated data into Terraform state
@@ -371,8 +761,38 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	client := r.data.Client
 
+	userID := data.ID.ValueUUID()
+	workspaces, err := userWorkspaces(ctx, client, userID, data.Username.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to list workspaces of user, got error: %s", err))
+		return
+	}
+	if len(workspaces) > 0 {
+		switch data.OnDestroy.ValueString() {
+		case userOnDestroyDeleteWorkspaces, userOnDestroyOrphan:
+			orphan := data.OnDestroy.ValueString() == userOnDestroyOrphan
+			for _, ws := range workspaces {
+				err = deleteWorkspace(ctx, client, ws, orphan, r.data.JobTimeout)
+				if err != nil {
+					resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete workspace %q, got error: %s", ws.Name, err))
+					return
+				}
+			}
+		default:
+			names := make([]string, 0, len(workspaces))
+			for _, ws := range workspaces {
+				names = append(names, ws.Name)
+			}
+			resp.Diagnostics.AddError("User Has Workspaces",
+				fmt.Sprintf("User %q can't be deleted, as they own %d workspace(s): %s. "+
+					"Delete the workspaces, or set on_destroy to delete_workspaces or orphan and apply before destroying.",
+					data.Username.ValueString(), len(workspaces), strings.Join(names, ", ")))
+			return
+		}
+	}
+
 	tflog.Info(ctx, "deleting user")
-	err := client.DeleteUser(ctx, data.ID.ValueUUID())
+	err = client.DeleteUser(ctx, userID)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete user, got error: %s", err))
 		return
@@ -382,16 +802,319 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 // Req.ID can be either a UUID or a username.
 func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	_, err := uuid.Parse(req.ID)
-	if err == nil {
-		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	client := r.data.Client
+	var user codersdk.User
+	if strings.Contains(req.ID, "@") {
+		var found bool
+		var err error
+		user, found, err = userByEmail(ctx, client, req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to find user with email %q, got error: %s", req.ID, err))
+			return
+		}
+		if !found {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("No user with email %q exists", req.ID))
+			return
+		}
+	} else {
+		var err error
+		user, err = client.User(ctx, req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Client Error", "Invalid import ID format, expected a single UUID, a valid username or an email")
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), user.ID.String())...)
+
+	// Import the organization roles and terminal font as managed, so
+	// configuration generated from the state is complete. Leaving the roles
+	// out of the configuration stops managing them, rather than removing them.
+	placeholder := make(map[string]attr.Value, len(user.OrganizationIDs))
+	for _, orgID := range user.OrganizationIDs {
+		placeholder[orgID.String()] = types.SetValueMust(types.StringType, nil)
+	}
+	orgRoles, diags := r.readOrganizationRoles(ctx, user.ID, types.MapValueMust(organizationRolesType, placeholder))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	client := r.data.Client
-	user, err := client.User(ctx, req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_roles"), orgRoles)...)
+	appearance, err := userAppearanceSettings(ctx, client, user.ID)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", "Invalid import ID format, expected a single UUID or a valid username")
+		// The deployment doesn't support terminal font preferences.
+		tflog.Warn(ctx, "unable to get user appearance, not importing terminal font", map[string]any{
+			"error": err.Error(),
+		})
 		return
 	}
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), user.ID.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("terminal_font"), appearance.TerminalFont)...)
+	// The remaining attributes are populated by Read.
+}
+
+// organizationChanges returns the organizations to add the user to and
+// remove them from to match the planned organizations. If organizations are
+// unmanaged, there are none.
+func organizationChanges(ctx context.Context, user codersdk.User, planned types.Set) (add, remove []string, diags diag.Diagnostics) {
+	if planned.IsNull() || planned.IsUnknown() {
+		return nil, nil, diags
+	}
+	var plannedOrgs []UUID
+	diags.Append(planned.ElementsAs(ctx, &plannedOrgs, false)...)
+	if diags.HasError() {
+		return nil, nil, diags
+	}
+	add, remove = memberDiff(user.OrganizationIDs, plannedOrgs)
+	return add, remove, diags
+}
+
+func organizationIDsValue(orgIDs []uuid.UUID) types.Set {
+	values := make([]attr.Value, 0, len(orgIDs))
+	for _, id := range orgIDs {
+		values = append(values, UUIDValue(id))
+	}
+	return types.SetValueMust(UUIDType, values)
+}
+
+func (r *UserResource) addToOrganizations(ctx context.Context, userID uuid.UUID, orgIDs []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, orgID := range orgIDs {
+		tflog.Info(ctx, "adding user to organization", map[string]any{
+			"organization_id": orgID,
+		})
+		_, err := r.data.Client.PostOrganizationMember(ctx, uuid.MustParse(orgID), userID.String())
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to add user to organization %s, got error: %s", orgID, err))
+			return diags
+		}
+	}
+	return diags
+}
+
+func (r *UserResource) removeFromOrganizations(ctx context.Context, userID uuid.UUID, orgIDs []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, orgID := range orgIDs {
+		tflog.Info(ctx, "removing user from organization", map[string]any{
+			"organization_id": orgID,
+		})
+		err := r.data.Client.DeleteOrganizationMember(ctx, uuid.MustParse(orgID), userID.String())
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to remove user from organization %s, got error: %s", orgID, err))
+			return diags
+		}
+	}
+	return diags
+}
+
+// updateOrganizationRoles sets the user's roles in each organization in
+// planned, and removes their roles in organizations only in prior. If planned
+// is null, the roles are no longer managed, and are left as they are.
+func (r *UserResource) updateOrganizationRoles(ctx context.Context, userID uuid.UUID, planned, prior types.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if planned.IsNull() {
+		return diags
+	}
+	plannedRoles := map[string][]string{}
+	diags.Append(planned.ElementsAs(ctx, &plannedRoles, false)...)
+	priorRoles := map[string][]string{}
+	if !prior.IsNull() {
+		diags.Append(prior.ElementsAs(ctx, &priorRoles, false)...)
+	}
+	if diags.HasError() {
+		return diags
+	}
+	for orgID := range priorRoles {
+		if _, ok := plannedRoles[orgID]; !ok {
+			plannedRoles[orgID] = []string{}
+		}
+	}
+
+	for orgID, roles := range plannedRoles {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			diags.AddAttributeError(path.Root("organization_roles"), "Invalid Organization ID", fmt.Sprintf("Unable to parse organization ID %q: %s", orgID, err))
+			return diags
+		}
+		tflog.Info(ctx, "updating user organization roles", map[string]any{
+			"organization_id": orgID,
+			"new_roles":       roles,
+		})
+		_, err = r.data.Client.UpdateOrganizationMemberRoles(ctx, id, userID.String(), codersdk.UpdateRoles{
+			Roles: roles,
+		})
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to update user roles in organization %s, got error: %s", orgID, err))
+			return diags
+		}
+	}
+	if len(plannedRoles) > 0 {
+		tflog.Info(ctx, "successfully updated user organization roles")
+	}
+	return diags
+}
+
+// readOrganizationRoles returns the user's roles in each organization in
+// orgRoles. Organizations the user isn't a member of are omitted.
+func (r *UserResource) readOrganizationRoles(ctx context.Context, userID uuid.UUID, orgRoles types.Map) (types.Map, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var current map[string][]string
+	diags.Append(orgRoles.ElementsAs(ctx, &current, false)...)
+	if diags.HasError() {
+		return orgRoles, diags
+	}
+	result := make(map[string]attr.Value, len(current))
+	for orgID := range current {
+		id, err := uuid.Parse(orgID)
+		if err != nil {
+			continue
+		}
+		members, err := r.data.Client.OrganizationMembers(ctx, id)
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get members of organization %s, got error: %s", orgID, err))
+			return orgRoles, diags
+		}
+		for _, member := range members {
+			if member.UserID != userID {
+				continue
+			}
+			roles := make([]attr.Value, 0, len(member.Roles))
+			for _, role := range member.Roles {
+				roles = append(roles, types.StringValue(role.Name))
+			}
+			result[orgID] = types.SetValueMust(types.StringType, roles)
+		}
+	}
+	return types.MapValueMust(organizationRolesType, result), diags
+}
+
+// userWorkspaces returns the workspaces owned by the user.
+func userWorkspaces(ctx context.Context, client *codersdk.Client, userID uuid.UUID, username string) ([]codersdk.Workspace, error) {
+	workspaces, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.Workspace, error) {
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{
+			Owner:  username,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+		})
+		return res.Workspaces, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]codersdk.Workspace, 0, len(workspaces))
+	for _, ws := range workspaces {
+		if ws.OwnerID == userID {
+			owned = append(owned, ws)
+		}
+	}
+	return owned, nil
+}
+
+// findExistingUser returns the user with the given username, or otherwise
+// the given email. A user with the username but a different email can't be
+// adopted, as emails can't be changed.
+func findExistingUser(ctx context.Context, client *codersdk.Client, username, email string) (codersdk.User, bool, error) {
+	user, err := client.User(ctx, username)
+	if err == nil {
+		if !strings.EqualFold(user.Email, email) {
+			return codersdk.User{}, false, fmt.Errorf("user %q exists with a different email %q", username, user.Email)
+		}
+		return user, true, nil
+	}
+	if !isNotFound(err) {
+		return codersdk.User{}, false, err
+	}
+	// The username is updated to match.
+	return userByEmail(ctx, client, email)
+}
+
+// userByEmail returns the user with the given email, compared
+// case-insensitively, if one exists.
+func userByEmail(ctx context.Context, client *codersdk.Client, email string) (codersdk.User, bool, error) {
+	users, err := listAll(ctx, func(page codersdk.Pagination) ([]codersdk.User, error) {
+		res, err := client.Users(ctx, codersdk.UsersRequest{
+			Search:     email,
+			Pagination: page,
+		})
+		return res.Users, err
+	})
+	if err != nil {
+		return codersdk.User{}, false, err
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return user, true, nil
+		}
+	}
+	return codersdk.User{}, false, nil
+}
+
+// randomPassword returns a random password that is never stored.
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// requestOneTimePasscode emails a user a one-time passcode to reset their
+// password.
+func requestOneTimePasscode(ctx context.Context, client *codersdk.Client, email string) error {
+	res, err := client.Request(ctx, http.MethodPost, "/api/v2/users/otp/request", map[string]string{
+		"email": email,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// updateUserLoginType converts the login type of a user as an admin.
+func updateUserLoginType(ctx context.Context, client *codersdk.Client, userID uuid.UUID, loginType codersdk.LoginType) error {
+	res, err := client.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/login-type", userID), map[string]any{
+		"login_type": loginType,
+	})
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// userAppearance is the appearance settings of a user. codersdk doesn't
+// support the terminal font yet, so the settings are sent directly.
+type userAppearance struct {
+	ThemePreference string `json:"theme_preference"`
+	TerminalFont    string `json:"terminal_font,omitempty"`
+}
+
+func updateUserAppearance(ctx context.Context, client *codersdk.Client, userID uuid.UUID, appearance userAppearance) error {
+	res, err := client.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/appearance", userID), appearance)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return codersdk.ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func userAppearanceSettings(ctx context.Context, client *codersdk.Client, userID uuid.UUID) (userAppearance, error) {
+	res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/appearance", userID), nil)
+	if err != nil {
+		return userAppearance{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return userAppearance{}, codersdk.ReadBodyAsError(res)
+	}
+	var appearance userAppearance
+	return appearance, json.NewDecoder(res.Body).Decode(&appearance)
 }
@@ -2,13 +2,16 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
 	"text/template"
 
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/terraform-provider-coderd/integration"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,6 +21,9 @@ func TestAccUserResource(t *testing.T) {
 	}
 	ctx := context.Background()
 	client := integration.StartCoder(ctx, t, "user_acc", false)
+	firstUser, err := client.User(ctx, codersdk.Me)
+	require.NoError(t, err)
+	orgID := firstUser.OrganizationIDs[0].String()
 
 	cfg1 := testAccUserResourceConfig{
 		URL:       client.URL.String(),
@@ -32,14 +38,26 @@ func TestAccUserResource(t *testing.T) {
 
 	cfg2 := cfg1
 	cfg2.Username = PtrTo("exampleNew")
+	cfg2.ThemePreference = PtrTo("dark")
 
 	cfg3 := cfg2
 	cfg3.Name = PtrTo("Example New")
+	cfg3.Organizations = PtrTo([]string{orgID})
+	cfg3.OrganizationRoles = map[string][]string{
+		orgID: {"organization-admin"},
+	}
+
+	cfg3Suspended := cfg3
+	cfg3Suspended.Suspended = PtrTo(true)
 
 	cfg4 := cfg3
 	cfg4.LoginType = PtrTo("github")
 	cfg4.Password = nil
 
+	cfg5 := cfg4
+	cfg5.OrganizationRoles = nil
+
+	var userID string
 	resource.Test(t, resource.TestCase{
 		IsUnitTest:               true,
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -58,6 +76,10 @@ func TestAccUserResource(t *testing.T) {
 					resource.TestCheckResourceAttr("coderd_user.test", "login_type", "password"),
 					resource.TestCheckResourceAttr("coderd_user.test", "password", "SomeSecurePassword!"),
 					resource.TestCheckResourceAttr("coderd_user.test", "suspended", "false"),
+					resource.TestCheckResourceAttrWith("coderd_user.test", "id", func(value string) error {
+						userID = value
+						return nil
+					}),
 				),
 			},
 			// Import by ID
@@ -65,8 +87,9 @@ func TestAccUserResource(t *testing.T) {
 				ResourceName:      "coderd_user.test",
 				ImportState:       true,
 				ImportStateVerify: true,
-				// We can't pull the password from the API.
-				ImportStateVerifyIgnore: []string{"password"},
+				// We can't pull the password from the API. The organization
+				// roles and terminal font are imported, but not configured.
+				ImportStateVerifyIgnore: []string{"password", "organization_roles", "terminal_font"},
 			},
 			// ImportState by username
 			{
@@ -74,8 +97,19 @@ func TestAccUserResource(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 				ImportStateId:     "example",
-				// We can't pull the password from the API.
-				ImportStateVerifyIgnore: []string{"password"},
+				// We can't pull the password from the API. The organization
+				// roles and terminal font are imported, but not configured.
+				ImportStateVerifyIgnore: []string{"password", "organization_roles", "terminal_font"},
+			},
+			// ImportState by email
+			{
+				ResourceName:      "coderd_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "example@coder.com",
+				// We can't pull the password from the API. The organization
+				// roles and terminal font are imported, but not configured.
+				ImportStateVerifyIgnore: []string{"password", "organization_roles", "terminal_font"},
 			},
 			// Update and Read testing
 			{
@@ -83,6 +117,7 @@ func TestAccUserResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("coderd_user.test", "username", "exampleNew"),
 					resource.TestCheckResourceAttr("coderd_user.test", "name", "Example User"),
+					resource.TestCheckResourceAttr("coderd_user.test", "theme_preference", "dark"),
 				),
 			},
 			{
@@ -90,13 +125,91 @@ func TestAccUserResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("coderd_user.test", "username", "exampleNew"),
 					resource.TestCheckResourceAttr("coderd_user.test", "name", "Example New"),
+					resource.TestCheckResourceAttr("coderd_user.test", "organizations.#", "1"),
+					resource.TestCheckResourceAttr("coderd_user.test", "organizations.0", orgID),
+					resource.TestCheckResourceAttr("coderd_user.test", "organization_roles.%", "1"),
+					resource.TestCheckResourceAttr("coderd_user.test", "organization_roles."+orgID+".#", "1"),
+					resource.TestCheckTypeSetElemAttr("coderd_user.test", "organization_roles."+orgID+".*", "organization-admin"),
+				),
+			},
+			// Suspend
+			{
+				Config: cfg3Suspended.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_user.test", "suspended", "true"),
 				),
 			},
-			// Replace triggered
+			// Reactivate, and login type converted in place
 			{
 				Config: cfg4.String(t),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("coderd_user.test", "login_type", "github"),
+					resource.TestCheckResourceAttr("coderd_user.test", "suspended", "false"),
+					resource.TestCheckResourceAttrWith("coderd_user.test", "id", func(value string) error {
+						if value != userID {
+							return fmt.Errorf("expected user %s to be converted, but it was replaced by %s", userID, value)
+						}
+						return nil
+					}),
+				),
+			},
+			// Organization roles no longer managed are left as they are
+			{
+				Config: cfg5.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("coderd_user.test", "organization_roles.%"),
+					func(*terraform.State) error {
+						members, err := client.OrganizationMembers(ctx, firstUser.OrganizationIDs[0])
+						if err != nil {
+							return err
+						}
+						for _, member := range members {
+							if member.UserID.String() != userID {
+								continue
+							}
+							for _, role := range member.Roles {
+								if role.Name == "organization-admin" {
+									return nil
+								}
+							}
+						}
+						return fmt.Errorf("expected user %s to keep the organization-admin role", userID)
+					},
+				),
+			},
+		},
+	})
+
+	existing, err := client.CreateUser(ctx, codersdk.CreateUserRequest{
+		Email:          "existing@coder.com",
+		Username:       "existing",
+		UserLoginType:  codersdk.LoginTypeNone,
+		OrganizationID: firstUser.OrganizationIDs[0],
+	})
+	require.NoError(t, err)
+
+	cfgAdopt := testAccUserResourceConfig{
+		URL:           client.URL.String(),
+		Token:         client.SessionToken(),
+		Username:      PtrTo("existing"),
+		Name:          PtrTo("Existing User"),
+		Email:         PtrTo("existing@coder.com"),
+		LoginType:     PtrTo("github"),
+		AdoptExisting: PtrTo(true),
+	}
+
+	resource.Test(t, resource.TestCase{
+		IsUnitTest:               true,
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfgAdopt.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("coderd_user.test", "id", existing.ID.String()),
+					resource.TestCheckResourceAttr("coderd_user.test", "name", "Existing User"),
+					resource.TestCheckResourceAttr("coderd_user.test", "login_type", "github"),
+					resource.TestCheckResourceAttr("coderd_user.test", "adopt_existing", "true"),
 				),
 			},
 		},
@@ -114,6 +227,11 @@ type testAccUserResourceConfig struct {
 	LoginType *string
 	Password  *string
 	Suspended *bool
+
+	Organizations     *[]string
+	OrganizationRoles map[string][]string
+	ThemePreference   *string
+	AdoptExisting     *bool
 }
 
 func (c testAccUserResourceConfig) String(t *testing.T) string {
@@ -132,6 +250,17 @@ resource "coderd_user" "test" {
 	login_type = {{orNull .LoginType}}
 	password   = {{orNull .Password}}
 	suspended  = {{orNull .Suspended}}
+
+	theme_preference = {{orNull .ThemePreference}}
+	adopt_existing   = {{orNull .AdoptExisting}}
+	organizations    = {{orNull .Organizations}}
+{{- if .OrganizationRoles}}
+	organization_roles = {
+	{{- range $org, $roles := .OrganizationRoles}}
+		"{{$org}}" = [{{range $i, $role := $roles}}{{if $i}}, {{end}}"{{$role}}"{{end}}]
+	{{- end}}
+	}
+{{- end}}
 }
 `
 	// Define template functions
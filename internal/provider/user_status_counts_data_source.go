@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &UserStatusCountsDataSource{}
+
+func NewUserStatusCountsDataSource() datasource.DataSource {
+	return &UserStatusCountsDataSource{}
+}
+
+// UserStatusCountsDataSource defines the data source implementation.
+type UserStatusCountsDataSource struct {
+	data *CoderdProviderData
+}
+
+// UserStatusCountsDataSourceModel describes the data source data model.
+type UserStatusCountsDataSourceModel struct {
+	TZHourOffset types.Int64 `tfsdk:"tz_hour_offset"`
+
+	Active    []UserStatusCount `tfsdk:"active"`
+	Dormant   []UserStatusCount `tfsdk:"dormant"`
+	Suspended []UserStatusCount `tfsdk:"suspended"`
+}
+
+type UserStatusCount struct {
+	Date  types.String `tfsdk:"date"`
+	Count types.Int64  `tfsdk:"count"`
+}
+
+// userStatusCountsResponse mirrors the user status counts returned by the
+// Coder API, which were added after the version of codersdk this provider is
+// built against.
+type userStatusCountsResponse struct {
+	StatusCounts map[codersdk.UserStatus][]struct {
+		Date  time.Time
+		Count int64
+	} `json:"status_counts"`
+}
+
+func (d *UserStatusCountsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_status_counts"
+}
+
+func (d *UserStatusCountsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	countsAttribute := func(status string) schema.ListNestedAttribute {
+		return schema.ListNestedAttribute{
+			MarkdownDescription: fmt.Sprintf("The number of %s users on each day, ordered by date.", status),
+			Computed:            true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"date": schema.StringAttribute{
+						MarkdownDescription: "The date of the entry, formatted as `YYYY-MM-DD`.",
+						Computed:            true,
+					},
+					"count": schema.Int64Attribute{
+						MarkdownDescription: fmt.Sprintf("The number of %s users on the date.", status),
+						Computed:            true,
+					},
+				},
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The number of active, dormant and suspended users on the Coder deployment over time, from the insights API. " +
+			"Requires Coder " + minVersionUserStatusCounts + " or later.",
+
+		Attributes: map[string]schema.Attribute{
+			"tz_hour_offset": schema.Int64Attribute{
+				MarkdownDescription: "The timezone offset from UTC, in hours, used to bucket users into days. Defaults to 0.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(-12, 14),
+				},
+			},
+			"active":    countsAttribute("active"),
+			"dormant":   countsAttribute("dormant"),
+			"suspended": countsAttribute("suspended"),
+		},
+	}
+}
+
+func (d *UserStatusCountsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *UserStatusCountsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data UserStatusCountsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(d.data.requireServerVersion("coderd_user_status_counts", minVersionUserStatusCounts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.TZHourOffset.IsNull() {
+		data.TZHourOffset = types.Int64Value(0)
+	}
+
+	counts, err := userStatusCounts(ctx, d.data.Client, int(data.TZHourOffset.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get user status counts, got error: %s", err))
+		return
+	}
+	data.Active = convertUserStatusCounts(counts, codersdk.UserStatusActive)
+	data.Dormant = convertUserStatusCounts(counts, codersdk.UserStatusDormant)
+	data.Suspended = convertUserStatusCounts(counts, codersdk.UserStatusSuspended)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func userStatusCounts(ctx context.Context, client *codersdk.Client, tzOffset int) (userStatusCountsResponse, error) {
+	res, err := client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/insights/user-status-counts?tz_offset=%d", tzOffset), nil)
+	if err != nil {
+		return userStatusCountsResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return userStatusCountsResponse{}, codersdk.ReadBodyAsError(res)
+	}
+	var counts userStatusCountsResponse
+	return counts, json.NewDecoder(res.Body).Decode(&counts)
+}
+
+func convertUserStatusCounts(counts userStatusCountsResponse, status codersdk.UserStatus) []UserStatusCount {
+	entries := make([]UserStatusCount, 0, len(counts.StatusCounts[status]))
+	for _, entry := range counts.StatusCounts[status] {
+		entries = append(entries, UserStatusCount{
+			Date:  types.StringValue(entry.Date.Format(time.DateOnly)),
+			Count: types.Int64Value(entry.Count),
+		})
+	}
+	return entries
+}
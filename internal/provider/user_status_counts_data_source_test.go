@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/terraform-provider-coderd/integration"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccUserStatusCountsDataSource(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests are disabled.")
+	}
+	ctx := context.Background()
+	client := integration.StartCoder(ctx, t, "user_status_counts_data_acc", false)
+	buildInfo, err := client.BuildInfo(ctx)
+	require.NoError(t, err)
+	if err := checkServerVersion(buildInfo.Version, minVersionUserStatusCounts); err != nil {
+		t.Skipf("User status counts are unsupported: %s", err)
+	}
+
+	cfg := testAccUserStatusCountsDataSourceConfig{
+		URL:   client.URL.String(),
+		Token: client.SessionToken(),
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: cfg.String(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.coderd_user_status_counts.test", "tz_hour_offset", "0"),
+					resource.TestCheckResourceAttrSet("data.coderd_user_status_counts.test", "active.#"),
+					resource.TestCheckResourceAttrSet("data.coderd_user_status_counts.test", "dormant.#"),
+					resource.TestCheckResourceAttrSet("data.coderd_user_status_counts.test", "suspended.#"),
+				),
+			},
+		},
+	})
+}
+
+func TestUserStatusCounts(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/insights/user-status-counts" || r.URL.Query().Get("tz_offset") != "-5" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status_counts":{
+			"active":[{"date":"2025-01-01T00:00:00-05:00","count":10},{"date":"2025-01-02T00:00:00-05:00","count":12}],
+			"suspended":[{"date":"2025-01-01T00:00:00-05:00","count":1}]
+		}}`))
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	counts, err := userStatusCounts(context.Background(), codersdk.New(srvURL), -5)
+	require.NoError(t, err)
+	require.Equal(t, []UserStatusCount{
+		{Date: types.StringValue("2025-01-01"), Count: types.Int64Value(10)},
+		{Date: types.StringValue("2025-01-02"), Count: types.Int64Value(12)},
+	}, convertUserStatusCounts(counts, codersdk.UserStatusActive))
+	require.Len(t, convertUserStatusCounts(counts, codersdk.UserStatusSuspended), 1)
+	require.Empty(t, convertUserStatusCounts(counts, codersdk.UserStatusDormant))
+
+	_, err = userStatusCounts(context.Background(), codersdk.New(srvURL), 0)
+	require.Error(t, err)
+}
+
+type testAccUserStatusCountsDataSourceConfig struct {
+	URL   string
+	Token string
+}
+
+func (c testAccUserStatusCountsDataSourceConfig) String(t *testing.T) string {
+	t.Helper()
+	tpl := `
+provider coderd {
+	url   = "{{.URL}}"
+	token = "{{.Token}}"
+}
+
+data "coderd_user_status_counts" "test" {}
+`
+
+	buf := strings.Builder{}
+	tmpl, err := template.New("userStatusCountsDataSource").Parse(tpl)
+	require.NoError(t, err)
+
+	err = tmpl.Execute(&buf, c)
+	require.NoError(t, err)
+	return buf.String()
+}
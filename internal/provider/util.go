@@ -1,20 +1,33 @@
 package provider
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
-	"path/filepath"
 	"regexp"
+	"sort"
 
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
 	nameValidRegex           = regexp.MustCompile("^[a-zA-Z0-9]+(?:-[a-zA-Z0-9]+)*$")
 	templateVersionNameRegex = regexp.MustCompile(`^[a-zA-Z0-9]+(?:[_.-]{1}[a-zA-Z0-9]+)*$`)
 	displayNameRegex         = regexp.MustCompile(`^[^\s](.*[^\s])?$`)
+	uuidRegex                = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	semverRegex              = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?$`)
 )
 
 func PtrTo[T any](v T) *T {
@@ -65,12 +78,84 @@ func PrintOrNull(v any) string {
 	}
 }
 
-func computeDirectoryHash(directory string) (string, error) {
-	var files []string
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// hashWorkers is the number of files read at once when hashing a directory.
+const hashWorkers = 8
+
+// computeDirectoryHash returns a hash of the files in the directory that are
+// not excluded by the rules. Only the relative paths and contents of the files
+// are hashed, so file modes, timestamps and the OS don't affect the hash. If
+// normalizeLineEndings is true, CRLF line endings are hashed as LF.
+//
+// Files are read concurrently, but hashed in order of their paths, so the
+// hash is deterministic.
+func computeDirectoryHash(directory string, rules ignoreRules, normalizeLineEndings bool) (string, error) {
+	files := map[string]string{}
+	err := walkIncluded(directory, rules, func(path, rel string, info os.FileInfo) error {
+		if info.Mode().IsRegular() {
+			files[rel] = path
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	results := make([]chan result, len(rels))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	// Reads are started in order, and a slot is released once a file is
+	// hashed, so at most hashWorkers files are held in memory.
+	slots := make(chan struct{}, hashWorkers)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for i, rel := range rels {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			go func() {
+				data, err := os.ReadFile(files[rel])
+				if err == nil && normalizeLineEndings {
+					data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+				}
+				results[i] <- result{data: data, err: err}
+			}()
+		}
+	}()
+
+	hash := sha256.New()
+	for i, rel := range rels {
+		res := <-results[i]
+		<-slots
+		if res.err != nil {
+			return "", res.err
 		}
+		data := res.data
+		// Length-prefix each field so different trees can't hash the same.
+		_, _ = fmt.Fprintf(hash, "%d:%s%d:", len(rel), rel, len(data))
+		_, _ = hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// computeLegacyDirectoryHash returns the hash of the directory computed by
+// previous versions of the provider, which only hashed the concatenated file
+// contents. It's used to match versions pushed before the hash changed.
+func computeLegacyDirectoryHash(directory string, rules ignoreRules) (string, error) {
+	var files []string
+	err := walkIncluded(directory, rules, func(path, _ string, info os.FileInfo) error {
 		if !info.IsDir() {
 			files = append(files, path)
 		}
@@ -91,6 +176,195 @@ func computeDirectoryHash(directory string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
+// isNotFound returns whether the error is a response from Coder that the
+// requested object doesn't exist.
+func isNotFound(err error) bool {
+	var sdkErr *codersdk.Error
+	return errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound
+}
+
+// isConflict returns whether the error is a response from Coder that the
+// object conflicts with an existing one.
+func isConflict(err error) bool {
+	var sdkErr *codersdk.Error
+	return errors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusConflict
+}
+
+// planOrganizationID plans the default organization when organization_id
+// isn't configured, rather than leaving it unknown, and replaces the resource
+// when the planned organization differs from its current one. Without this,
+// unsetting organization_id never moves a resource to the default
+// organization. Unless the default organization is configured, it depends on
+// the organizations of the provider's user, so the current organization is
+// kept instead.
+func planOrganizationID(ctx context.Context, data *CoderdProviderData, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	orgPath := path.Root("organization_id")
+	var planned UUID
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, orgPath, &planned)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if planned.IsNull() {
+		planned = UUIDValue(data.DefaultOrganizationID)
+		if !data.DefaultOrganizationConfigured && !req.State.Raw.IsNull() {
+			var current UUID
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, orgPath, &current)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if !current.IsNull() {
+				planned = current
+			}
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, orgPath, planned)...)
+	}
+	if planned.IsUnknown() || req.State.Raw.IsNull() {
+		return
+	}
+	var current UUID
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, orgPath, &current)...)
+	if !current.IsNull() && !planned.Equal(current) {
+		resp.RequiresReplace = append(resp.RequiresReplace, orgPath)
+	}
+}
+
+// rollbackCreate deletes a resource that was created, but whose later create
+// steps failed, and reports whether it was deleted. Terraform taints resources
+// that are saved with errors when created, and replaces them on the next apply,
+// so rather than being saved the resource is deleted, and the next apply
+// creates it again. The errors are still reported.
+func rollbackCreate(ctx context.Context, resp *resource.CreateResponse, kind string, deleteFn func(context.Context) error) bool {
+	tflog.Info(ctx, "deleting partially created "+kind)
+	err := deleteFn(context.WithoutCancel(ctx))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to delete partially created %s, so it's saved to the state and will be replaced: %s", kind, err))
+		return false
+	}
+	return true
+}
+
+// savePartialCreate saves a resource that was created, but whose later create
+// steps failed and which couldn't be deleted, so it isn't left unmanaged. The
+// state holds the planned values, with those still unknown set to null, and is
+// tainted by Terraform, so the next apply replaces it.
+func savePartialCreate(ctx context.Context, resp *resource.CreateResponse, kind string, data any) {
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	raw, err := tftypes.Transform(resp.State.Raw, func(_ *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if !value.IsKnown() {
+			return tftypes.NewValue(value.Type(), nil), nil
+		}
+		return value, nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to save partially created %s: %s", kind, err))
+		return
+	}
+	resp.State.Raw = raw
+}
+
+// plannedChange returns the planned value of a string attribute when the
+// resource is being created or the attribute is changing, and the value is
+// known. T is the value type of the attribute.
+func plannedChange[T basetypes.StringValuable](ctx context.Context, req resource.ModifyPlanRequest, attr path.Path) (string, bool, diag.Diagnostics) {
+	var planned, current T
+	diags := req.Plan.GetAttribute(ctx, attr, &planned)
+	if diags.HasError() || planned.IsUnknown() || planned.IsNull() {
+		return "", false, diags
+	}
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.GetAttribute(ctx, attr, &current)...)
+		if diags.HasError() || planned.Equal(current) {
+			return "", false, diags
+		}
+	}
+	value, d := planned.ToStringValue(ctx)
+	diags.Append(d...)
+	return value.ValueString(), !diags.HasError(), diags
+}
+
+// checkUsersExist returns an error for each of the users that doesn't exist,
+// and a warning for each that is suspended, on the attribute referencing
+// them. Invalid and unknown IDs are skipped, as they're reported elsewhere.
+func checkUsersExist(ctx context.Context, client *codersdk.Client, attr path.Path, ids []string) (diags diag.Diagnostics) {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			continue
+		}
+		user, err := client.User(ctx, id)
+		if isNotFound(err) {
+			diags.AddAttributeError(attr, "User Not Found", fmt.Sprintf("No user with ID %s exists.", id))
+			continue
+		}
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get user %s, got error: %s", id, err))
+			return diags
+		}
+		if user.Status == codersdk.UserStatusSuspended {
+			diags.AddAttributeWarning(attr, "User Suspended", fmt.Sprintf("User %q (%s) is suspended.", user.Username, id))
+		}
+	}
+	return diags
+}
+
+// checkGroupsExist returns an error for each of the groups that doesn't
+// exist, on the attribute referencing them. Invalid and unknown IDs are
+// skipped, as they're reported elsewhere.
+func checkGroupsExist(ctx context.Context, client *codersdk.Client, attr path.Path, ids []string) (diags diag.Diagnostics) {
+	for _, id := range ids {
+		groupID, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		_, err = client.Group(ctx, groupID)
+		if isNotFound(err) {
+			diags.AddAttributeError(attr, "Group Not Found", fmt.Sprintf("No group with ID %s exists.", id))
+			continue
+		}
+		if err != nil {
+			diags.AddError("Client Error", fmt.Sprintf("Unable to get group %s, got error: %s", id, err))
+			return diags
+		}
+	}
+	return diags
+}
+
+// listPageSize is the number of results to request per page when listing.
+const listPageSize = 100
+
+// listAll calls list with successive pages of results until a page isn't
+// full, so results aren't truncated by the server's page size.
+func listAll[T any](ctx context.Context, list func(page codersdk.Pagination) ([]T, error)) ([]T, error) {
+	var all []T
+	for offset := 0; ; offset += listPageSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := list(codersdk.Pagination{
+			Limit:  listPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < listPageSize {
+			return all, nil
+		}
+	}
+}
+
+// workspaceByIDOrName gets a workspace by its ID, or if the ID is nil, by
+// the username of its owner and its name.
+func workspaceByIDOrName(ctx context.Context, client *codersdk.Client, id uuid.UUID, owner, name string) (codersdk.Workspace, error) {
+	if id != uuid.Nil {
+		return client.Workspace(ctx, id)
+	}
+	return client.WorkspaceByOwnerAndName(ctx, owner, name, codersdk.WorkspaceOptions{})
+}
+
 // memberDiff returns the members to add and remove from the group, given the current members and the planned members.
 // plannedMembers is deliberately our custom type, as Terraform cannot automatically produce `[]uuid.UUID` from a set.
 func memberDiff(curMembers []uuid.UUID, plannedMembers []UUID) (add, remove []string) {
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeDirectoryHash(t *testing.T) {
+	t.Parallel()
+	hashFiles := func(t *testing.T, files map[string]string, normalizeLineEndings bool) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		}
+		hash, err := computeDirectoryHash(dir, nil, normalizeLineEndings)
+		require.NoError(t, err)
+		return hash
+	}
+
+	t.Run("IgnoresMetadata", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		path := filepath.Join(dir, "main.tf")
+		require.NoError(t, os.WriteFile(path, []byte("resource {}"), 0o600))
+		before, err := computeDirectoryHash(dir, nil, false)
+		require.NoError(t, err)
+
+		require.NoError(t, os.Chmod(path, 0o755))
+		require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(-time.Hour)))
+		after, err := computeDirectoryHash(dir, nil, false)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	})
+
+	t.Run("IncludesPaths", func(t *testing.T) {
+		t.Parallel()
+		original := hashFiles(t, map[string]string{"main.tf": "a", "vars.tf": "b"}, false)
+		require.Equal(t, original, hashFiles(t, map[string]string{"main.tf": "a", "vars.tf": "b"}, false))
+		require.NotEqual(t, original, hashFiles(t, map[string]string{"main.tf": "a", "variables.tf": "b"}, false))
+		require.NotEqual(t, original, hashFiles(t, map[string]string{"main.tf": "ab", "vars.tf": ""}, false))
+		require.NotEqual(t, original, hashFiles(t, map[string]string{"main.tf": "a", "modules/vars.tf": "b"}, false))
+	})
+
+	t.Run("Stable", func(t *testing.T) {
+		t.Parallel()
+		files := map[string]string{}
+		expected := sha256.New()
+		for i := range 3 * hashWorkers {
+			name := fmt.Sprintf("file%02d.tf", i)
+			files[name] = strings.Repeat("x", i)
+			_, _ = fmt.Fprintf(expected, "%d:%s%d:%s", len(name), name, i, files[name])
+		}
+		require.Equal(t, hex.EncodeToString(expected.Sum(nil)), hashFiles(t, files, false))
+	})
+
+	t.Run("NormalizeLineEndings", func(t *testing.T) {
+		t.Parallel()
+		lf := map[string]string{"main.tf": "line one\nline two\n"}
+		crlf := map[string]string{"main.tf": "line one\r\nline two\r\n"}
+		require.NotEqual(t, hashFiles(t, lf, false), hashFiles(t, crlf, false))
+		require.Equal(t, hashFiles(t, lf, true), hashFiles(t, crlf, true))
+	})
+}
+
+func TestListAll(t *testing.T) {
+	t.Parallel()
+	items := make([]int, 2*listPageSize+5)
+	for i := range items {
+		items[i] = i
+	}
+	var pages []codersdk.Pagination
+	all, err := listAll(context.Background(), func(page codersdk.Pagination) ([]int, error) {
+		pages = append(pages, page)
+		end := min(page.Offset+page.Limit, len(items))
+		return items[page.Offset:end], nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, items, all)
+	require.Len(t, pages, 3)
+	require.Equal(t, 2*listPageSize, pages[2].Offset)
+
+	_, err = listAll(context.Background(), func(codersdk.Pagination) ([]int, error) {
+		return nil, errors.New("list failed")
+	})
+	require.ErrorContains(t, err, "list failed")
+}
+
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := http.StatusNotFound
+		if strings.HasSuffix(r.URL.Path, "/forbidden") {
+			status = http.StatusForbidden
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message":"error"}`))
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client := codersdk.New(srvURL)
+
+	_, err = client.User(context.Background(), "missing")
+	require.True(t, isNotFound(err))
+	_, err = client.User(context.Background(), "forbidden")
+	require.False(t, isNotFound(err))
+	require.False(t, isNotFound(errors.New("not found")))
+	require.False(t, isNotFound(nil))
+}
+
+func TestCheckReferencesExist(t *testing.T) {
+	t.Parallel()
+	active, suspended, group := uuid.New(), uuid.New(), uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch r.URL.Path {
+		case "/api/v2/users/" + active.String():
+			body = map[string]any{"id": active, "username": "active", "status": codersdk.UserStatusActive}
+		case "/api/v2/users/" + suspended.String():
+			body = map[string]any{"id": suspended, "username": "suspended", "status": codersdk.UserStatusSuspended}
+		case "/api/v2/groups/" + group.String():
+			body = map[string]any{"id": group}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			body = codersdk.Response{Message: "not found"}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	client := codersdk.New(srvURL)
+	attr := path.Root("members")
+
+	diags := checkUsersExist(context.Background(), client, attr, []string{active.String(), "not-a-uuid"})
+	require.False(t, diags.HasError())
+	require.Empty(t, diags.Warnings())
+
+	diags = checkUsersExist(context.Background(), client, attr, []string{suspended.String()})
+	require.False(t, diags.HasError())
+	require.Len(t, diags.Warnings(), 1)
+
+	diags = checkUsersExist(context.Background(), client, attr, []string{uuid.NewString(), uuid.NewString()})
+	require.Len(t, diags.Errors(), 2)
+	require.Equal(t, "User Not Found", diags.Errors()[0].Summary())
+
+	diags = checkGroupsExist(context.Background(), client, attr, []string{group.String()})
+	require.False(t, diags.HasError())
+	diags = checkGroupsExist(context.Background(), client, attr, []string{uuid.NewString()})
+	require.Len(t, diags.Errors(), 1)
+}
+
+func TestSavePartialCreate(t *testing.T) {
+	t.Parallel()
+	resourceSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+	resp := &resource.CreateResponse{
+		State: tfsdk.State{
+			Schema: resourceSchema,
+			Raw:    tftypes.NewValue(resourceSchema.Type().TerraformType(context.Background()), nil),
+		},
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("name"), "Client Error", "update failed")
+	data := struct {
+		ID   types.String `tfsdk:"id"`
+		Name types.String `tfsdk:"name"`
+	}{
+		ID:   types.StringUnknown(),
+		Name: types.StringValue("example"),
+	}
+
+	savePartialCreate(context.Background(), resp, "example", &data)
+	require.Len(t, resp.Diagnostics.Errors(), 1)
+	require.Equal(t, path.Root("name"), resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath).Path())
+
+	var id, name types.String
+	diags := resp.State.GetAttribute(context.Background(), path.Root("id"), &id)
+	diags.Append(resp.State.GetAttribute(context.Background(), path.Root("name"), &name)...)
+	require.False(t, diags.HasError())
+	require.True(t, id.IsNull())
+	require.Equal(t, "example", name.ValueString())
+}
+
+func TestRollbackCreate(t *testing.T) {
+	t.Parallel()
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+		resp := &resource.CreateResponse{}
+		resp.Diagnostics.AddError("Client Error", "update failed")
+		require.True(t, rollbackCreate(context.Background(), resp, "example", func(context.Context) error {
+			return nil
+		}))
+		require.Len(t, resp.Diagnostics.Errors(), 1)
+	})
+	t.Run("DeleteFailed", func(t *testing.T) {
+		t.Parallel()
+		resp := &resource.CreateResponse{}
+		resp.Diagnostics.AddError("Client Error", "update failed")
+		require.False(t, rollbackCreate(context.Background(), resp, "example", func(context.Context) error {
+			return errors.New("delete failed")
+		}))
+		require.Len(t, resp.Diagnostics.Errors(), 2)
+	})
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"golang.org/x/mod/semver"
+)
+
+// Minimum deployment versions for features added after the oldest version
+// supported by the provider.
+const (
+	minVersionOneTimePasscode        = "v2.17.0"
+	minVersionUserStatusCounts       = "v2.19.0"
+	minVersionTemplateVersionPresets = "v2.20.0"
+)
+
+// checkServerVersion returns an error if the deployment version is older than
+// minimum. Development builds, which are versioned v0.0.0, satisfy any
+// minimum.
+func checkServerVersion(version, minimum string) error {
+	if !semver.IsValid(minimum) {
+		return fmt.Errorf("%q is not a valid semantic version", minimum)
+	}
+	if !semver.IsValid(version) {
+		return fmt.Errorf("deployment version %q is not a valid semantic version", version)
+	}
+	if semver.Major(version) == "v0" {
+		return nil
+	}
+	if semver.Compare(version, minimum) < 0 {
+		return fmt.Errorf("deployment version %s is older than the required version %s", version, minimum)
+	}
+	return nil
+}
+
+// requireServerVersion returns an error diagnostic if the deployment is too
+// old to support the feature.
+func (d *CoderdProviderData) requireServerVersion(feature, minimum string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d == nil || d.ServerVersion == "" {
+		return diags
+	}
+	if err := checkServerVersion(d.ServerVersion, minimum); err != nil {
+		diags.AddError("Unsupported Deployment Version",
+			fmt.Sprintf("%s requires Coder %s or later: %s", feature, minimum, err))
+	}
+	return diags
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// versionNameData is the data available to the `name_template` of a template
+// version.
+type versionNameData struct {
+	// Hash is the first 7 characters of the directory hash.
+	Hash string
+	// Timestamp is the UTC time the version is created, e.g. `20240601T150405`.
+	Timestamp string
+	// Date is the UTC date the version is created, e.g. `2024-06-01`.
+	Date string
+}
+
+func parseVersionNameTemplate(nameTemplate string) (*template.Template, error) {
+	return template.New("name_template").Option("missingkey=error").Parse(nameTemplate)
+}
+
+// renderVersionName renders the name of a template version from its
+// `name_template`.
+func renderVersionName(nameTemplate, directoryHash string, now time.Time) (string, error) {
+	tmpl, err := parseVersionNameTemplate(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid name_template: %w", err)
+	}
+	hash := directoryHash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	now = now.UTC()
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, versionNameData{
+		Hash:      hash,
+		Timestamp: now.Format("20060102T150405"),
+		Date:      now.Format(time.DateOnly),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render name_template: %w", err)
+	}
+	name := buf.String()
+	if len(name) < 1 || len(name) > 64 || !templateVersionNameRegex.MatchString(name) {
+		return "", fmt.Errorf("name_template rendered the invalid template version name %q: names must be between 1 and 64 characters, and alphanumeric with underscores and dots", name)
+	}
+	return name, nil
+}
+
+type versionNameTemplateValidator struct{}
+
+// NewVersionNameTemplateValidator validates that a string is a valid Go
+// template for a template version name.
+func NewVersionNameTemplateValidator() validator.String {
+	return &versionNameTemplateValidator{}
+}
+
+// Description implements validator.String.
+func (v *versionNameTemplateValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+// MarkdownDescription implements validator.String.
+func (v *versionNameTemplateValidator) MarkdownDescription(context.Context) string {
+	return "Validate that the value is a valid template for a template version name."
+}
+
+// ValidateString implements validator.String.
+func (v *versionNameTemplateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	// Render with placeholder values to catch unknown fields.
+	_, err := renderVersionName(req.ConfigValue.ValueString(), "0000000", time.Unix(0, 0))
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Name Template", err.Error())
+	}
+}
+
+var _ validator.String = &versionNameTemplateValidator{}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderVersionName(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, time.June, 1, 15, 4, 5, 0, time.FixedZone("UTC+2", 2*60*60))
+	hash := "abc1234def5678"
+
+	cases := []struct {
+		name         string
+		nameTemplate string
+		expected     string
+		err          string
+	}{
+		{
+			name:         "DateAndHash",
+			nameTemplate: "{{.Date}}-{{.Hash}}",
+			expected:     "2024-06-01-abc1234",
+		},
+		{
+			name:         "Timestamp",
+			nameTemplate: "release.{{.Timestamp}}",
+			expected:     "release.20240601T130405",
+		},
+		{
+			name:         "UnknownField",
+			nameTemplate: "{{.Branch}}",
+			err:          "failed to render name_template",
+		},
+		{
+			name:         "InvalidSyntax",
+			nameTemplate: "{{.Hash",
+			err:          "invalid name_template",
+		},
+		{
+			name:         "InvalidName",
+			nameTemplate: "{{.Hash}} {{.Date}}",
+			err:          "invalid template version name",
+		},
+		{
+			name:         "Empty",
+			nameTemplate: "",
+			err:          "invalid template version name",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			name, err := renderVersionName(c.nameTemplate, hash, now)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, name)
+		})
+	}
+}
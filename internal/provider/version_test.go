@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckServerVersion(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		version string
+		minimum string
+		err     string
+	}{
+		{
+			name:    "Equal",
+			version: "v2.14.2",
+			minimum: "v2.14.2",
+		},
+		{
+			name:    "Newer",
+			version: "v2.15.0+abc123",
+			minimum: "v2.14.2",
+		},
+		{
+			name:    "Older",
+			version: "v2.13.5",
+			minimum: "v2.14.0",
+			err:     "older than the required version",
+		},
+		{
+			name:    "Devel",
+			version: "v0.0.0-devel+abc123",
+			minimum: "v2.14.0",
+		},
+		{
+			name:    "InvalidMinimum",
+			version: "v2.14.0",
+			minimum: "2.14",
+			err:     "not a valid semantic version",
+		},
+		{
+			name:    "InvalidVersion",
+			version: "unknown",
+			minimum: "v2.14.0",
+			err:     "deployment version",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkServerVersion(c.version, c.minimum)
+			if c.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, c.err)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WorkspaceAgentsDataSource{}
+
+func NewWorkspaceAgentsDataSource() datasource.DataSource {
+	return &WorkspaceAgentsDataSource{}
+}
+
+// WorkspaceAgentsDataSource defines the data source implementation.
+type WorkspaceAgentsDataSource struct {
+	data *CoderdProviderData
+}
+
+// WorkspaceAgentsDataSourceModel describes the data source data model.
+type WorkspaceAgentsDataSourceModel struct {
+	WorkspaceID UUID         `tfsdk:"workspace_id"`
+	Owner       types.String `tfsdk:"owner"`
+	Name        types.String `tfsdk:"name"`
+
+	Agents []WorkspaceAgent `tfsdk:"agents"`
+}
+
+type WorkspaceAgent struct {
+	ID                       UUID           `tfsdk:"id"`
+	Name                     types.String   `tfsdk:"name"`
+	ResourceID               UUID           `tfsdk:"resource_id"`
+	OperatingSystem          types.String   `tfsdk:"operating_system"`
+	Architecture             types.String   `tfsdk:"architecture"`
+	Version                  types.String   `tfsdk:"version"`
+	Status                   types.String   `tfsdk:"status"`
+	LifecycleState           types.String   `tfsdk:"lifecycle_state"`
+	Directory                types.String   `tfsdk:"directory"`
+	ConnectionTimeoutSeconds types.Int64    `tfsdk:"connection_timeout_seconds"`
+	TroubleshootingURL       types.String   `tfsdk:"troubleshooting_url"`
+	LastConnectedAt          types.Int64    `tfsdk:"last_connected_at"`
+	Apps                     []WorkspaceApp `tfsdk:"apps"`
+}
+
+type WorkspaceApp struct {
+	ID           UUID         `tfsdk:"id"`
+	Slug         types.String `tfsdk:"slug"`
+	DisplayName  types.String `tfsdk:"display_name"`
+	URL          types.String `tfsdk:"url"`
+	External     types.Bool   `tfsdk:"external"`
+	Subdomain    types.Bool   `tfsdk:"subdomain"`
+	SharingLevel types.String `tfsdk:"sharing_level"`
+	Health       types.String `tfsdk:"health"`
+}
+
+func (d *WorkspaceAgentsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_agents"
+}
+
+func (d *WorkspaceAgentsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The agents of the latest build of a workspace, and their apps.",
+
+		Attributes: map[string]schema.Attribute{
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the workspace. This field will be populated if an owner and name are supplied.",
+				CustomType:          UUIDType,
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name")),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "The username of the owner of the workspace. Required if `name` is set. This field will be populated if an ID is supplied.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the workspace. This field will be populated if an ID is supplied.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("owner")),
+				},
+			},
+			"agents": schema.ListNestedAttribute{
+				MarkdownDescription: "The agents of the workspace.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the agent.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the agent.",
+							Computed:            true,
+						},
+						"resource_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the workspace resource the agent runs on.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"operating_system": schema.StringAttribute{
+							MarkdownDescription: "The operating system of the agent, such as `linux`.",
+							Computed:            true,
+						},
+						"architecture": schema.StringAttribute{
+							MarkdownDescription: "The architecture of the agent, such as `amd64`.",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "The version of the agent. Empty if the agent hasn't connected.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The connection status of the agent. One of `connecting`, `connected`, `disconnected` or `timeout`.",
+							Computed:            true,
+						},
+						"lifecycle_state": schema.StringAttribute{
+							MarkdownDescription: "The lifecycle state of the agent, such as `starting` or `ready`.",
+							Computed:            true,
+						},
+						"directory": schema.StringAttribute{
+							MarkdownDescription: "The working directory of the agent.",
+							Computed:            true,
+						},
+						"connection_timeout_seconds": schema.Int64Attribute{
+							MarkdownDescription: "The number of seconds after which the agent is considered to have timed out connecting.",
+							Computed:            true,
+						},
+						"troubleshooting_url": schema.StringAttribute{
+							MarkdownDescription: "The URL with troubleshooting steps for the agent.",
+							Computed:            true,
+						},
+						"last_connected_at": schema.Int64Attribute{
+							MarkdownDescription: "The Unix timestamp of when the agent last connected. Null if the agent has never connected.",
+							Computed:            true,
+						},
+						"apps": schema.ListNestedAttribute{
+							MarkdownDescription: "The apps of the agent.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										MarkdownDescription: "The ID of the app.",
+										CustomType:          UUIDType,
+										Computed:            true,
+									},
+									"slug": schema.StringAttribute{
+										MarkdownDescription: "The slug of the app.",
+										Computed:            true,
+									},
+									"display_name": schema.StringAttribute{
+										MarkdownDescription: "The display name of the app.",
+										Computed:            true,
+									},
+									"url": schema.StringAttribute{
+										MarkdownDescription: "The URL the app proxies to, or for external apps, the URL opened by the app.",
+										Computed:            true,
+									},
+									"external": schema.BoolAttribute{
+										MarkdownDescription: "Whether the app is an external app, opened directly rather than proxied by Coder.",
+										Computed:            true,
+									},
+									"subdomain": schema.BoolAttribute{
+										MarkdownDescription: "Whether the app is served on a subdomain of the deployment's wildcard access URL.",
+										Computed:            true,
+									},
+									"sharing_level": schema.StringAttribute{
+										MarkdownDescription: "Who the app is shared with. One of `owner`, `authenticated` or `public`.",
+										Computed:            true,
+									},
+									"health": schema.StringAttribute{
+										MarkdownDescription: "The health of the app. One of `disabled`, `initializing`, `healthy` or `unhealthy`.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspaceAgentsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *WorkspaceAgentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WorkspaceAgentsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	workspace, err := workspaceByIDOrName(ctx, client, data.WorkspaceID.ValueUUID(), data.Owner.ValueString(), data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get workspace, got error: %s", err))
+		return
+	}
+	data.WorkspaceID = UUIDValue(workspace.ID)
+	data.Owner = types.StringValue(workspace.OwnerName)
+	data.Name = types.StringValue(workspace.Name)
+
+	data.Agents = convertWorkspaceAgents(workspace)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// convertWorkspaceAgents returns the agents of the resources of the latest
+// build of the workspace.
+func convertWorkspaceAgents(workspace codersdk.Workspace) []WorkspaceAgent {
+	agents := []WorkspaceAgent{}
+	for _, res := range workspace.LatestBuild.Resources {
+		for _, agent := range res.Agents {
+			lastConnectedAt := types.Int64Null()
+			if agent.LastConnectedAt != nil {
+				lastConnectedAt = types.Int64Value(agent.LastConnectedAt.Unix())
+			}
+			apps := make([]WorkspaceApp, 0, len(agent.Apps))
+			for _, app := range agent.Apps {
+				apps = append(apps, WorkspaceApp{
+					ID:           UUIDValue(app.ID),
+					Slug:         types.StringValue(app.Slug),
+					DisplayName:  types.StringValue(app.DisplayName),
+					URL:          types.StringValue(app.URL),
+					External:     types.BoolValue(app.External),
+					Subdomain:    types.BoolValue(app.Subdomain),
+					SharingLevel: types.StringValue(string(app.SharingLevel)),
+					Health:       types.StringValue(string(app.Health)),
+				})
+			}
+			agents = append(agents, WorkspaceAgent{
+				ID:                       UUIDValue(agent.ID),
+				Name:                     types.StringValue(agent.Name),
+				ResourceID:               UUIDValue(res.ID),
+				OperatingSystem:          types.StringValue(agent.OperatingSystem),
+				Architecture:             types.StringValue(agent.Architecture),
+				Version:                  types.StringValue(agent.Version),
+				Status:                   types.StringValue(string(agent.Status)),
+				LifecycleState:           types.StringValue(string(agent.LifecycleState)),
+				Directory:                types.StringValue(agent.Directory),
+				ConnectionTimeoutSeconds: types.Int64Value(int64(agent.ConnectionTimeoutSeconds)),
+				TroubleshootingURL:       types.StringValue(agent.TroubleshootingURL),
+				LastConnectedAt:          lastConnectedAt,
+				Apps:                     apps,
+			})
+		}
+	}
+	return agents
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWorkspaceAgents(t *testing.T) {
+	t.Parallel()
+	resourceID := uuid.New()
+	agentID := uuid.New()
+	appID := uuid.New()
+	connectedAt := time.Unix(1700000000, 0)
+	workspace := codersdk.Workspace{
+		LatestBuild: codersdk.WorkspaceBuild{
+			Resources: []codersdk.WorkspaceResource{
+				{ID: uuid.New(), Type: "docker_volume"},
+				{
+					ID:   resourceID,
+					Type: "docker_container",
+					Agents: []codersdk.WorkspaceAgent{
+						{
+							ID:                       agentID,
+							Name:                     "main",
+							OperatingSystem:          "linux",
+							Architecture:             "amd64",
+							Status:                   codersdk.WorkspaceAgentConnected,
+							ConnectionTimeoutSeconds: 120,
+							LastConnectedAt:          &connectedAt,
+							Apps: []codersdk.WorkspaceApp{
+								{ID: appID, Slug: "code-server", URL: "http://localhost:8080", Subdomain: true, SharingLevel: codersdk.WorkspaceAppSharingLevelOwner},
+							},
+						},
+						{Name: "sidecar", Status: codersdk.WorkspaceAgentConnecting},
+					},
+				},
+			},
+		},
+	}
+
+	agents := convertWorkspaceAgents(workspace)
+	require.Len(t, agents, 2)
+	require.Equal(t, UUIDValue(agentID), agents[0].ID)
+	require.Equal(t, UUIDValue(resourceID), agents[0].ResourceID)
+	require.Equal(t, types.StringValue("connected"), agents[0].Status)
+	require.Equal(t, types.Int64Value(120), agents[0].ConnectionTimeoutSeconds)
+	require.Equal(t, types.Int64Value(connectedAt.Unix()), agents[0].LastConnectedAt)
+	require.Len(t, agents[0].Apps, 1)
+	require.Equal(t, UUIDValue(appID), agents[0].Apps[0].ID)
+	require.Equal(t, types.BoolValue(true), agents[0].Apps[0].Subdomain)
+	require.Equal(t, types.StringValue("owner"), agents[0].Apps[0].SharingLevel)
+
+	require.Equal(t, types.StringValue("sidecar"), agents[1].Name)
+	require.True(t, agents[1].LastConnectedAt.IsNull())
+	require.Empty(t, agents[1].Apps)
+
+	require.Empty(t, convertWorkspaceAgents(codersdk.Workspace{}))
+}
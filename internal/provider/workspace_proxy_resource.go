@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -60,8 +63,11 @@ func (r *WorkspaceProxyResource) Schema(ctx context.Context, req resource.Schema
 				Computed:            true,
 			},
 			"icon": schema.StringAttribute{
-				MarkdownDescription: "Relative path or external URL that specifies an icon to be displayed in the dashboard.",
+				MarkdownDescription: "Relative path or external URL that specifies an icon to be displayed in the dashboard. Must be an https URL, an image data URL, a path to a built-in emoji or icon (e.g. `/emojis/1f680.png` or `/icon/code.svg`), or an emoji shortcode.",
 				Required:            true,
+				Validators: []validator.String{
+					NewIconURLValidator(),
+				},
 			},
 			"session_token": schema.StringAttribute{
 				MarkdownDescription: "Session token for the workspace proxy.",
@@ -109,10 +115,30 @@ func (r *WorkspaceProxyResource) Create(ctx context.Context, req resource.Create
 	}
 
 	client := r.data.Client
-	wsp, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
-		Name:        data.Name.ValueString(),
-		DisplayName: data.DisplayName.ValueString(),
-		Icon:        data.Icon.ValueString(),
+	wsp, err := createIdempotently(ctx, "workspace proxy", func() (codersdk.UpdateWorkspaceProxyResponse, error) {
+		return client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name:        data.Name.ValueString(),
+			DisplayName: data.DisplayName.ValueString(),
+			Icon:        data.Icon.ValueString(),
+		})
+	}, func(start time.Time, _ error) (codersdk.UpdateWorkspaceProxyResponse, bool, error) {
+		proxy, err := client.WorkspaceProxyByName(ctx, data.Name.ValueString())
+		if isNotFound(err) {
+			return codersdk.UpdateWorkspaceProxyResponse{}, false, nil
+		}
+		if err != nil || !createdSince(proxy.CreatedAt, start) {
+			return codersdk.UpdateWorkspaceProxyResponse{}, false, err
+		}
+		// The session token is only returned when the proxy is created, so
+		// a new one is generated for the proxy we adopt.
+		wsp, err := client.PatchWorkspaceProxy(ctx, codersdk.PatchWorkspaceProxy{
+			ID:              proxy.ID,
+			Name:            proxy.Name,
+			DisplayName:     proxy.DisplayName,
+			Icon:            proxy.IconURL,
+			RegenerateToken: true,
+		})
+		return wsp, err == nil, err
 	})
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to create workspace proxy: %v", err))
@@ -141,6 +167,13 @@ func (r *WorkspaceProxyResource) Read(ctx context.Context, req resource.ReadRequ
 
 	client := r.data.Client
 	wsp, err := client.WorkspaceProxyByID(ctx, data.ID.ValueUUID())
+	if isNotFound(err) {
+		tflog.Warn(ctx, "workspace proxy not found, removing from state", map[string]any{
+			"id": data.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Failed to read workspace proxy: %v", err))
 		return
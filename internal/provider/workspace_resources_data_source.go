@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WorkspaceResourcesDataSource{}
+
+func NewWorkspaceResourcesDataSource() datasource.DataSource {
+	return &WorkspaceResourcesDataSource{}
+}
+
+// WorkspaceResourcesDataSource defines the data source implementation.
+type WorkspaceResourcesDataSource struct {
+	data *CoderdProviderData
+}
+
+// WorkspaceResourcesDataSourceModel describes the data source data model.
+type WorkspaceResourcesDataSourceModel struct {
+	WorkspaceID UUID         `tfsdk:"workspace_id"`
+	Owner       types.String `tfsdk:"owner"`
+	Name        types.String `tfsdk:"name"`
+
+	BuildID   UUID                `tfsdk:"build_id"`
+	DailyCost types.Int64         `tfsdk:"daily_cost"`
+	Resources []WorkspaceResource `tfsdk:"resources"`
+}
+
+type WorkspaceResource struct {
+	ID        UUID                        `tfsdk:"id"`
+	Type      types.String                `tfsdk:"type"`
+	Name      types.String                `tfsdk:"name"`
+	Hide      types.Bool                  `tfsdk:"hide"`
+	DailyCost types.Int64                 `tfsdk:"daily_cost"`
+	Metadata  []WorkspaceResourceMetadata `tfsdk:"metadata"`
+}
+
+type WorkspaceResourceMetadata struct {
+	Key       types.String `tfsdk:"key"`
+	Value     types.String `tfsdk:"value"`
+	Sensitive types.Bool   `tfsdk:"sensitive"`
+}
+
+func (d *WorkspaceResourcesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_resources"
+}
+
+func (d *WorkspaceResourcesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The resources of the latest build of a workspace, and their daily costs.",
+
+		Attributes: map[string]schema.Attribute{
+			"workspace_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the workspace. This field will be populated if an owner and name are supplied.",
+				CustomType:          UUIDType,
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name")),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "The username of the owner of the workspace. Required if `name` is set. This field will be populated if an ID is supplied.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the workspace. This field will be populated if an ID is supplied.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("owner")),
+				},
+			},
+			"build_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the latest build of the workspace.",
+				CustomType:          UUIDType,
+				Computed:            true,
+			},
+			"daily_cost": schema.Int64Attribute{
+				MarkdownDescription: "The daily cost of the latest build of the workspace, in quota credits.",
+				Computed:            true,
+			},
+			"resources": schema.ListNestedAttribute{
+				MarkdownDescription: "The resources of the latest build of the workspace.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the resource.",
+							CustomType:          UUIDType,
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The Terraform type of the resource, such as `docker_container`.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The Terraform name of the resource.",
+							Computed:            true,
+						},
+						"hide": schema.BoolAttribute{
+							MarkdownDescription: "Whether the resource is hidden in the Coder dashboard.",
+							Computed:            true,
+						},
+						"daily_cost": schema.Int64Attribute{
+							MarkdownDescription: "The daily cost of the resource, in quota credits.",
+							Computed:            true,
+						},
+						"metadata": schema.ListNestedAttribute{
+							MarkdownDescription: "The metadata of the resource, set with `coder_metadata` in the template.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"key": schema.StringAttribute{
+										MarkdownDescription: "The key of the metadata item.",
+										Computed:            true,
+									},
+									"value": schema.StringAttribute{
+										MarkdownDescription: "The value of the metadata item. Null if the item is sensitive, so it isn't stored in the state.",
+										Computed:            true,
+									},
+									"sensitive": schema.BoolAttribute{
+										MarkdownDescription: "Whether the metadata item is sensitive.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *WorkspaceResourcesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*CoderdProviderData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CoderdProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.data = data
+}
+
+func (d *WorkspaceResourcesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WorkspaceResourcesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := d.data.Client
+
+	workspace, err := workspaceByIDOrName(ctx, client, data.WorkspaceID.ValueUUID(), data.Owner.ValueString(), data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to get workspace, got error: %s", err))
+		return
+	}
+	data.WorkspaceID = UUIDValue(workspace.ID)
+	data.Owner = types.StringValue(workspace.OwnerName)
+	data.Name = types.StringValue(workspace.Name)
+	data.BuildID = UUIDValue(workspace.LatestBuild.ID)
+	data.DailyCost = types.Int64Value(int64(workspace.LatestBuild.DailyCost))
+	data.Resources = convertWorkspaceResources(workspace.LatestBuild.Resources)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// convertWorkspaceResources converts the resources of a workspace build,
+// leaving the values of sensitive metadata null.
+func convertWorkspaceResources(resources []codersdk.WorkspaceResource) []WorkspaceResource {
+	converted := make([]WorkspaceResource, 0, len(resources))
+	for _, res := range resources {
+		metadata := make([]WorkspaceResourceMetadata, 0, len(res.Metadata))
+		for _, item := range res.Metadata {
+			value := types.StringValue(item.Value)
+			if item.Sensitive {
+				value = types.StringNull()
+			}
+			metadata = append(metadata, WorkspaceResourceMetadata{
+				Key:       types.StringValue(item.Key),
+				Value:     value,
+				Sensitive: types.BoolValue(item.Sensitive),
+			})
+		}
+		converted = append(converted, WorkspaceResource{
+			ID:        UUIDValue(res.ID),
+			Type:      types.StringValue(res.Type),
+			Name:      types.StringValue(res.Name),
+			Hide:      types.BoolValue(res.Hide),
+			DailyCost: types.Int64Value(int64(res.DailyCost)),
+			Metadata:  metadata,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertWorkspaceResources(t *testing.T) {
+	t.Parallel()
+	resourceID := uuid.New()
+	resources := convertWorkspaceResources([]codersdk.WorkspaceResource{
+		{
+			ID:        resourceID,
+			Type:      "aws_instance",
+			Name:      "dev",
+			DailyCost: 10,
+			Metadata: []codersdk.WorkspaceResourceMetadata{
+				{Key: "instance_type", Value: "t3.large"},
+				{Key: "password", Value: "hunter2", Sensitive: true},
+			},
+		},
+		{Type: "aws_ebs_volume", Name: "home", Hide: true},
+	})
+
+	require.Len(t, resources, 2)
+	require.Equal(t, UUIDValue(resourceID), resources[0].ID)
+	require.Equal(t, types.StringValue("aws_instance"), resources[0].Type)
+	require.Equal(t, types.Int64Value(10), resources[0].DailyCost)
+	require.Equal(t, []WorkspaceResourceMetadata{
+		{Key: types.StringValue("instance_type"), Value: types.StringValue("t3.large"), Sensitive: types.BoolValue(false)},
+		{Key: types.StringValue("password"), Value: types.StringNull(), Sensitive: types.BoolValue(true)},
+	}, resources[0].Metadata)
+	require.Equal(t, types.BoolValue(true), resources[1].Hide)
+	require.Empty(t, resources[1].Metadata)
+}